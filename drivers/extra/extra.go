@@ -1,6 +1,10 @@
 package extra
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+)
 
 const (
 	// Error event
@@ -11,6 +15,9 @@ const (
 
 	// NewValues event
 	NewValues = "new-values"
+
+	// NewResult event
+	NewResult = "new-result"
 )
 
 // ErrCallFunctionReturnCodeMismatch is error when call function return code that mistmach the provided
@@ -21,4 +28,5 @@ type ExtraReader interface {
 	ValueRead(name string) (val interface{}, err error)
 	ValuesRead() (vals map[string]interface{}, err error)
 	FunctionCall(name string, parameters string) (val int, err error)
+	FunctionCallWithResult(name string, parameters string) (res *client.CallFunctionResult, err error)
 }
@@ -1,6 +1,7 @@
 package extra
 
 import (
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	"gobot.io/x/gobot"
 )
 
@@ -9,6 +10,7 @@ type FunctionDriver struct {
 	functionName string
 	parameters   string
 	returnCode   int
+	result       *client.CallFunctionResult
 	name         string
 	connection   ExtraReader
 	gobot.Eventer
@@ -25,6 +27,7 @@ func NewFunctionDriver(a ExtraReader, functionName string, parameters string) *F
 	}
 
 	b.AddEvent(Error)
+	b.AddEvent(NewResult)
 
 	return b
 }
@@ -51,7 +54,35 @@ func (b *FunctionDriver) FunctionName() string { return b.functionName }
 func (b *FunctionDriver) Parameters() string { return b.parameters }
 
 // SetParameters set the FunctionDriver parameters
-func (b *FunctionDriver) SetParameters(parameters string) { b.parameters = parameters }
+// It return error and keep the current parameters if they can't be safely encoded
+func (b *FunctionDriver) SetParameters(parameters string) (err error) {
+	if _, err = client.EncodeParams(parameters); err != nil {
+		return err
+	}
+	b.parameters = parameters
+	return
+}
+
+// SetParams set the FunctionDriver parameters for multi-arguments function
+func (b *FunctionDriver) SetParams(params *client.Params) (err error) {
+	parameters, err := params.Build()
+	if err != nil {
+		return err
+	}
+	b.parameters = parameters
+	return
+}
+
+// Result returns the response of the last function call, nil if not yet called
+func (b *FunctionDriver) Result() *client.CallFunctionResult { return b.result }
+
+// Message returns the message sent by board on the last function call
+func (b *FunctionDriver) Message() string {
+	if b.result == nil {
+		return ""
+	}
+	return b.result.Message
+}
 
 // Connection returns the FunctionDriver Connection
 func (b *FunctionDriver) Connection() gobot.Connection { return b.connection.(gobot.Connection) }
@@ -59,15 +90,18 @@ func (b *FunctionDriver) Connection() gobot.Connection { return b.connection.(go
 // Call run function
 //
 // Emits the Events:
+//	NewResult *client.CallFunctionResult - The function response
 //	Error error - On function call error
 func (b *FunctionDriver) Call() (err error) {
-	ret, err := b.connection.FunctionCall(b.functionName, b.parameters)
+	res, err := b.connection.FunctionCallWithResult(b.functionName, b.parameters)
 	if err != nil {
 		b.Publish(Error, err)
 		return err
 	}
+	b.result = res
+	b.Publish(NewResult, res)
 
-	if ret != b.returnCode {
+	if res.ReturnValue != b.returnCode {
 		b.Publish(Error, ErrCallFunctionReturnCodeMismatch)
 		return ErrCallFunctionReturnCodeMismatch
 	}
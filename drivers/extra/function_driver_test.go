@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
 )
@@ -37,8 +38,17 @@ func TestFunctionDriverFunctionName(t *testing.T) {
 func TestFunctionDriverParameters(t *testing.T) {
 	g, _ := initTestFunctionDriver()
 	gobottest.Assert(t, g.Parameters(), "param1")
-	g.SetParameters("param2")
+	gobottest.Assert(t, g.SetParameters("param2"), nil)
 	gobottest.Assert(t, g.Parameters(), "param2")
+
+	// Too long parameters
+	gobottest.Refute(t, g.SetParameters(strings.Repeat("a", client.MaxParamsLength+1)), nil)
+	gobottest.Assert(t, g.Parameters(), "param2")
+
+	// Multi-arguments
+	gobottest.Assert(t, g.SetParams(client.NewParams().Set("pump", "on").Set("duration", "12")), nil)
+	gobottest.Assert(t, g.Parameters(), "pump=on,duration=12")
+	gobottest.Refute(t, g.SetParams(client.NewParams().Add("a,b")), nil)
 }
 
 func TestFunctionDriverStart(t *testing.T) {
@@ -71,3 +81,16 @@ func TestFunctionDriverCall(t *testing.T) {
 	gobottest.Assert(t, d.Call(), err)
 
 }
+
+func TestFunctionDriverResult(t *testing.T) {
+	d, a := initTestFunctionDriver()
+
+	// Not yet called
+	gobottest.Assert(t, d.Result() == nil, true)
+	gobottest.Assert(t, d.Message(), "")
+
+	a.TestAdaptorMessage("Pump started")
+	gobottest.Assert(t, d.Call(), nil)
+	gobottest.Assert(t, d.Result().ReturnValue, 0)
+	gobottest.Assert(t, d.Message(), "Pump started")
+}
@@ -1,6 +1,10 @@
 package extra
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+)
 
 type extraTestAdaptor struct {
 	name                    string
@@ -9,6 +13,7 @@ type extraTestAdaptor struct {
 	testAdaptorValueRead    func(name string) (val interface{}, err error)
 	testAdaptorValuesRead   func() (vals map[string]interface{}, err error)
 	testAdaptorFunctionCall func(name string, parameters string) (val int, err error)
+	testAdaptorMessage      string
 }
 
 func (t *extraTestAdaptor) TestAdaptorValueRead(f func(name string) (val interface{}, err error)) {
@@ -27,6 +32,12 @@ func (t *extraTestAdaptor) TestAdaptorFunctionCall(f func(name string, parameter
 	t.testAdaptorFunctionCall = f
 }
 
+func (t *extraTestAdaptor) TestAdaptorMessage(message string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.testAdaptorMessage = message
+}
+
 func (t *extraTestAdaptor) ValueRead(name string) (val interface{}, err error) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
@@ -42,6 +53,18 @@ func (t *extraTestAdaptor) FunctionCall(name string, parameters string) (val int
 	defer t.mtx.Unlock()
 	return t.testAdaptorFunctionCall(name, parameters)
 }
+func (t *extraTestAdaptor) FunctionCallWithResult(name string, parameters string) (res *client.CallFunctionResult, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	val, err := t.testAdaptorFunctionCall(name, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return &client.CallFunctionResult{
+		ReturnValue: val,
+		Message:     t.testAdaptorMessage,
+	}, nil
+}
 func (t *extraTestAdaptor) Connect() (err error)  { return }
 func (t *extraTestAdaptor) Finalize() (err error) { return }
 func (t *extraTestAdaptor) Name() string          { return t.name }
@@ -0,0 +1,179 @@
+package extra
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueTypeError is returned when a value can't be converted to the expected type
+type ValueTypeError struct {
+	Name     string
+	Value    interface{}
+	Expected string
+}
+
+// Error implement error interface
+func (e *ValueTypeError) Error() string {
+	return fmt.Sprintf("Variable %s has value %v of type %T, can't convert it to %s", e.Name, e.Value, e.Value, e.Expected)
+}
+
+// ReadInt read the variable and convert it to int
+func ReadInt(r ExtraReader, name string) (val int, err error) {
+	value, err := r.ValueRead(name)
+	if err != nil {
+		return val, err
+	}
+	return ToInt(name, value)
+}
+
+// ReadFloat read the variable and convert it to float64
+func ReadFloat(r ExtraReader, name string) (val float64, err error) {
+	value, err := r.ValueRead(name)
+	if err != nil {
+		return val, err
+	}
+	return ToFloat(name, value)
+}
+
+// ReadBool read the variable and convert it to bool
+func ReadBool(r ExtraReader, name string) (val bool, err error) {
+	value, err := r.ValueRead(name)
+	if err != nil {
+		return val, err
+	}
+	return ToBool(name, value)
+}
+
+// ReadString read the variable and convert it to string
+func ReadString(r ExtraReader, name string) (val string, err error) {
+	value, err := r.ValueRead(name)
+	if err != nil {
+		return val, err
+	}
+	return ToString(name, value)
+}
+
+// ReadDuration read the variable and convert it to time.Duration
+// Numeric value is multiplied by unit, string value is parsed with time.ParseDuration
+func ReadDuration(r ExtraReader, name string, unit time.Duration) (val time.Duration, err error) {
+	value, err := r.ValueRead(name)
+	if err != nil {
+		return val, err
+	}
+	return ToDuration(name, value, unit)
+}
+
+// ToInt convert aREST value to int
+// It failed if the value is not an integer
+func ToInt(name string, value interface{}) (val int, err error) {
+	// Keep precision of large integer
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil && i <= math.MaxInt && i >= math.MinInt {
+			return int(i), nil
+		}
+	}
+
+	f, err := ToFloat(name, value)
+	if err != nil {
+		return val, &ValueTypeError{Name: name, Value: value, Expected: "int"}
+	}
+	if f != math.Trunc(f) || f > math.MaxInt || f < math.MinInt {
+		return val, &ValueTypeError{Name: name, Value: value, Expected: "int"}
+	}
+
+	return int(f), nil
+}
+
+// ToFloat convert aREST value to float64
+func ToFloat(name string, value interface{}) (val float64, err error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case json.Number:
+		if val, err = v.Float64(); err == nil {
+			return val, nil
+		}
+	case string:
+		if val, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return val, nil
+		}
+	}
+
+	return 0, &ValueTypeError{Name: name, Value: value, Expected: "float"}
+}
+
+// ToBool convert aREST value to bool
+// Number 0 / 1 and string like "true", "false", "on", "off" are accepted
+func ToBool(name string, value interface{}) (val bool, err error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "on", "1":
+			return true, nil
+		case "false", "off", "0":
+			return false, nil
+		}
+	default:
+		if f, err := ToFloat(name, value); err == nil {
+			switch f {
+			case 1:
+				return true, nil
+			case 0:
+				return false, nil
+			}
+		}
+	}
+
+	return false, &ValueTypeError{Name: name, Value: value, Expected: "bool"}
+}
+
+// ToString convert aREST value to string
+func ToString(name string, value interface{}) (val string, err error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case nil:
+		return "", &ValueTypeError{Name: name, Value: value, Expected: "string"}
+	default:
+		if f, err := ToFloat(name, value); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+	}
+
+	return "", &ValueTypeError{Name: name, Value: value, Expected: "string"}
+}
+
+// ToDuration convert aREST value to time.Duration
+// Numeric value is multiplied by unit, string value is parsed with time.ParseDuration
+func ToDuration(name string, value interface{}, unit time.Duration) (val time.Duration, err error) {
+	if s, ok := value.(string); ok {
+		if val, err = time.ParseDuration(strings.TrimSpace(s)); err == nil {
+			return val, nil
+		}
+	}
+
+	f, err := ToFloat(name, value)
+	if err != nil {
+		return val, &ValueTypeError{Name: name, Value: value, Expected: "duration"}
+	}
+
+	return time.Duration(f * float64(unit)), nil
+}
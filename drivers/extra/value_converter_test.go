@@ -0,0 +1,129 @@
+package extra
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestToInt(t *testing.T) {
+	val, err := ToInt("test", float64(10))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 10)
+
+	val, err = ToInt("test", json.Number("12"))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 12)
+
+	// Large integer keep precision
+	val, err = ToInt("test", json.Number("9007199254740993"))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 9007199254740993)
+
+	val, err = ToInt("test", "14")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 14)
+
+	_, err = ToInt("test", 10.5)
+	gobottest.Refute(t, err, nil)
+
+	_, err = ToInt("test", true)
+	_, ok := err.(*ValueTypeError)
+	gobottest.Assert(t, ok, true)
+}
+
+func TestToFloat(t *testing.T) {
+	val, err := ToFloat("test", 10.5)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 10.5)
+
+	val, err = ToFloat("test", "24.3")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 24.3)
+
+	_, err = ToFloat("test", nil)
+	gobottest.Refute(t, err, nil)
+
+	_, err = ToFloat("test", "bad")
+	gobottest.Refute(t, err, nil)
+}
+
+func TestToBool(t *testing.T) {
+	val, err := ToBool("test", true)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, true)
+
+	val, err = ToBool("test", float64(0))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, false)
+
+	val, err = ToBool("test", "on")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, true)
+
+	_, err = ToBool("test", float64(2))
+	gobottest.Refute(t, err, nil)
+
+	_, err = ToBool("test", "bad")
+	gobottest.Refute(t, err, nil)
+}
+
+func TestToString(t *testing.T) {
+	val, err := ToString("test", "pool")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, "pool")
+
+	val, err = ToString("test", 24.5)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, "24.5")
+
+	_, err = ToString("test", nil)
+	gobottest.Refute(t, err, nil)
+}
+
+func TestToDuration(t *testing.T) {
+	val, err := ToDuration("test", float64(10), time.Second)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 10*time.Second)
+
+	val, err = ToDuration("test", "1m30s", time.Second)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 90*time.Second)
+
+	_, err = ToDuration("test", "bad", time.Second)
+	gobottest.Refute(t, err, nil)
+}
+
+func TestReadTyped(t *testing.T) {
+	a := newExtraTestAdaptor()
+
+	i, err := ReadInt(a, "test")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, i, 99)
+
+	f, err := ReadFloat(a, "test")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, f, float64(99))
+
+	s, err := ReadString(a, "test")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, s, "99")
+
+	d, err := ReadDuration(a, "test", time.Millisecond)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, d, 99*time.Millisecond)
+
+	_, err = ReadBool(a, "test")
+	gobottest.Refute(t, err, nil)
+
+	// Read error
+	readErr := errors.New("read error")
+	a.TestAdaptorValueRead(func(name string) (val interface{}, err error) {
+		return nil, readErr
+	})
+	_, err = ReadInt(a, "test")
+	gobottest.Assert(t, err, readErr)
+}
@@ -0,0 +1,267 @@
+package extra
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/pkg/errors"
+)
+
+// TagName is the struct tag used to bind aREST variables on struct fields.
+//
+// The tag format is `arest:"name[,optional][,unit=<duration>][,convert=<converter>]"`:
+//
+//	optional: no error if the variable is not exposed by the board
+//	unit: the unit used for numeric value on time.Duration field, like ms, s or m (default s)
+//	convert: the name of converter registered with ValuesDecoder.RegisterConverter
+const TagName = "arest"
+
+// ValueConverter permit to convert the raw aREST value before set it on struct field
+type ValueConverter func(value interface{}) (interface{}, error)
+
+// ValuesDecodeError is returned when some variables are missing or mistyped
+type ValuesDecodeError struct {
+	Missing  []string
+	Mistyped map[string]error
+}
+
+// Error implement error interface
+func (e *ValuesDecodeError) Error() string {
+	msgs := make([]string, 0, 2)
+	if len(e.Missing) > 0 {
+		msgs = append(msgs, fmt.Sprintf("missing variables: %s", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Mistyped) > 0 {
+		names := make([]string, 0, len(e.Mistyped))
+		for name := range e.Mistyped {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		mistyped := make([]string, 0, len(names))
+		for _, name := range names {
+			mistyped = append(mistyped, e.Mistyped[name].Error())
+		}
+		msgs = append(msgs, fmt.Sprintf("mistyped variables: %s", strings.Join(mistyped, "; ")))
+	}
+
+	return fmt.Sprintf("Can't decode values: %s", strings.Join(msgs, ", "))
+}
+
+// Is permit to use errors.Is with client.ErrVariableNotFound when some variables are missing
+func (e *ValuesDecodeError) Is(target error) bool {
+	return target == client.ErrVariableNotFound && len(e.Missing) > 0
+}
+
+// ValuesDecoder permit to fill struct from aREST variables
+type ValuesDecoder struct {
+	converters map[string]ValueConverter
+}
+
+// NewValuesDecoder return a new ValuesDecoder without converter
+func NewValuesDecoder() *ValuesDecoder {
+	return &ValuesDecoder{
+		converters: make(map[string]ValueConverter),
+	}
+}
+
+// RegisterConverter permit to add converter usable with `convert=<name>` tag option
+func (d *ValuesDecoder) RegisterConverter(name string, converter ValueConverter) {
+	d.converters[name] = converter
+}
+
+// DecodeValues fill the struct pointed by out from values with the default decoder
+func DecodeValues(values map[string]interface{}, out interface{}) (err error) {
+	return NewValuesDecoder().Decode(values, out)
+}
+
+// ReadValuesInto read all values and fill the struct pointed by out with the default decoder
+func ReadValuesInto(r ExtraReader, out interface{}) (err error) {
+	return NewValuesDecoder().Read(r, out)
+}
+
+// Read read all values and fill the struct pointed by out
+func (d *ValuesDecoder) Read(r ExtraReader, out interface{}) (err error) {
+	values, err := r.ValuesRead()
+	if err != nil {
+		return err
+	}
+
+	return d.Decode(values, out)
+}
+
+// Decode fill the struct pointed by out from values
+func (d *ValuesDecoder) Decode(values map[string]interface{}, out interface{}) (err error) {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("Decode need a non nil pointer on struct, got %T", out)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	decodeErr := &ValuesDecodeError{
+		Missing:  make([]string, 0),
+		Mistyped: make(map[string]error),
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup(TagName)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		opts, err := parseTag(tag)
+		if err != nil {
+			return errors.Wrapf(err, "Bad tag on field %s", field.Name)
+		}
+		if opts.name == "" {
+			opts.name = field.Name
+		}
+
+		value, ok := values[opts.name]
+		if !ok {
+			if !opts.optional {
+				decodeErr.Missing = append(decodeErr.Missing, opts.name)
+			}
+			continue
+		}
+
+		if opts.converter != "" {
+			converter, ok := d.converters[opts.converter]
+			if !ok {
+				return errors.Errorf("Converter %s not found for field %s", opts.converter, field.Name)
+			}
+			if value, err = converter(value); err != nil {
+				decodeErr.Mistyped[opts.name] = errors.Wrapf(err, "Variable %s", opts.name)
+				continue
+			}
+		}
+
+		if err = setField(rv.Field(i), opts, value); err != nil {
+			decodeErr.Mistyped[opts.name] = err
+		}
+	}
+
+	if len(decodeErr.Missing) > 0 || len(decodeErr.Mistyped) > 0 {
+		sort.Strings(decodeErr.Missing)
+		return decodeErr
+	}
+
+	return nil
+}
+
+type tagOptions struct {
+	name      string
+	optional  bool
+	unit      time.Duration
+	converter string
+}
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+func parseTag(tag string) (opts *tagOptions, err error) {
+	parts := strings.Split(tag, ",")
+	opts = &tagOptions{
+		name: strings.TrimSpace(parts[0]),
+		unit: time.Second,
+	}
+
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "optional":
+			opts.optional = true
+		case part == "required":
+			opts.optional = false
+		case strings.HasPrefix(part, "unit="):
+			unit, ok := durationUnits[strings.TrimPrefix(part, "unit=")]
+			if !ok {
+				return nil, errors.Errorf("Unit %s not supported", strings.TrimPrefix(part, "unit="))
+			}
+			opts.unit = unit
+		case strings.HasPrefix(part, "convert="):
+			opts.converter = strings.TrimPrefix(part, "convert=")
+		default:
+			return nil, errors.Errorf("Option %s not supported", part)
+		}
+	}
+
+	return opts, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setField(field reflect.Value, opts *tagOptions, value interface{}) (err error) {
+	if field.Type() == durationType {
+		d, err := ToDuration(opts.name, value, opts.unit)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := ToInt(opts.name, value)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(int64(i)) {
+			return &ValueTypeError{Name: opts.name, Value: value, Expected: field.Type().String()}
+		}
+		field.SetInt(int64(i))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := ToInt(opts.name, value)
+		if err != nil {
+			return err
+		}
+		if i < 0 || field.OverflowUint(uint64(i)) {
+			return &ValueTypeError{Name: opts.name, Value: value, Expected: field.Type().String()}
+		}
+		field.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		f, err := ToFloat(opts.name, value)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := ToBool(opts.name, value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.String:
+		s, err := ToString(opts.name, value)
+		if err != nil {
+			return err
+		}
+		field.SetString(s)
+	case reflect.Interface:
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		rv := reflect.ValueOf(value)
+		if !rv.Type().AssignableTo(field.Type()) {
+			return &ValueTypeError{Name: opts.name, Value: value, Expected: field.Type().String()}
+		}
+		field.Set(rv)
+	default:
+		return errors.Errorf("Field type %s not supported for variable %s", field.Type().String(), opts.name)
+	}
+
+	return nil
+}
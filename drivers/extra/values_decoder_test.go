@@ -0,0 +1,87 @@
+package extra
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testPool struct {
+	Temp     float64       `arest:"temperature"`
+	Pump     bool          `arest:"pump_on"`
+	Mode     string        `arest:"mode,optional"`
+	Cycles   uint8         `arest:"cycles"`
+	Filter   time.Duration `arest:"filter_duration,unit=m"`
+	TempF    float64       `arest:"temperature,convert=fahrenheit"`
+	Raw      interface{}   `arest:"raw,optional"`
+	Internal string
+}
+
+func TestDecodeValues(t *testing.T) {
+	values := map[string]interface{}{
+		"temperature":     float64(20),
+		"pump_on":         float64(1),
+		"cycles":          float64(3),
+		"filter_duration": float64(90),
+		"raw":             "test",
+	}
+
+	d := NewValuesDecoder()
+	d.RegisterConverter("fahrenheit", func(value interface{}) (interface{}, error) {
+		c, err := ToFloat("temperature", value)
+		if err != nil {
+			return nil, err
+		}
+		return c*9/5 + 32, nil
+	})
+
+	pool := &testPool{}
+	gobottest.Assert(t, d.Decode(values, pool), nil)
+	gobottest.Assert(t, pool.Temp, float64(20))
+	gobottest.Assert(t, pool.TempF, float64(68))
+	gobottest.Assert(t, pool.Pump, true)
+	gobottest.Assert(t, pool.Mode, "")
+	gobottest.Assert(t, pool.Cycles, uint8(3))
+	gobottest.Assert(t, pool.Filter, 90*time.Minute)
+	gobottest.Assert(t, pool.Raw, "test")
+
+	// Missing and mistyped variables
+	values = map[string]interface{}{
+		"temperature": "hot",
+		"cycles":      float64(300),
+	}
+	err := d.Decode(values, &testPool{})
+	decodeErr, ok := err.(*ValuesDecodeError)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, decodeErr.Missing, []string{"filter_duration", "pump_on"})
+	gobottest.Assert(t, len(decodeErr.Mistyped), 2)
+	gobottest.Assert(t, errors.Is(err, client.ErrVariableNotFound), true)
+
+	// Unknown converter
+	gobottest.Refute(t, DecodeValues(map[string]interface{}{"temperature": float64(20)}, &testPool{}), nil)
+
+	// Not a pointer on struct
+	gobottest.Refute(t, DecodeValues(values, testPool{}), nil)
+}
+
+func TestReadValuesInto(t *testing.T) {
+	a := newExtraTestAdaptor()
+
+	type board struct {
+		Test int `arest:"test"`
+	}
+
+	b := &board{}
+	gobottest.Assert(t, ReadValuesInto(a, b), nil)
+	gobottest.Assert(t, b.Test, 99)
+
+	// Read error
+	readErr := errors.New("read error")
+	a.TestAdaptorValuesRead(func() (vals map[string]interface{}, err error) {
+		return nil, readErr
+	})
+	gobottest.Assert(t, ReadValuesInto(a, b), readErr)
+}
@@ -0,0 +1,26 @@
+package arest
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// AnalogRead retrieves analog value from specified pin.
+// Pin can be provided as number ("0") or with analog prefix ("A0")
+func (a *Adaptor) AnalogRead(pin string) (val int, err error) {
+
+	p, err := analogPin(pin)
+	if err != nil {
+		return val, err
+	}
+	ctx := context.TODO()
+
+	return a.Board.AnalogRead(ctx, p)
+}
+
+// analogPin permit to convert analog pin name like "A0" to pin number
+func analogPin(pin string) (p int, err error) {
+	pin = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(pin)), "A")
+	return strconv.Atoi(pin)
+}
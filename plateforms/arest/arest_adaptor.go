@@ -2,13 +2,17 @@ package arest
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	log "github.com/sirupsen/logrus"
 	"gobot.io/x/gobot"
 )
 
-type arestBoard interface {
+// Board is the interface to implement to drive an aREST board throught any transport.
+// client.Protocol implement all commands, so a transport just need to embed it and add the connection handling.
+type Board interface {
 	// Connect permit to open connection on board
 	Connect(ctx context.Context) error
 
@@ -18,6 +22,12 @@ type arestBoard interface {
 	// Reconnect permit to reopen connection on board
 	Reconnect(ctx context.Context) error
 
+	// Info permit to get the board identity
+	Info(ctx context.Context) (info *client.BoardInfo, err error)
+
+	// State permit to get the connection state, it publish state-changed event with client.StateChange when it change
+	State() client.State
+
 	// SetPinMode permit to set pin mode
 	SetPinMode(ctx context.Context, pin int, mode string) (err error)
 
@@ -27,15 +37,24 @@ type arestBoard interface {
 	// DigitalRead permit to read level from pin
 	DigitalRead(ctx context.Context, pin int) (level int, err error)
 
-	// ReadValue permit to read user variable
+	// AnalogWrite permit to write PWM value on pin
+	AnalogWrite(ctx context.Context, pin int, value int) (err error)
+
+	// AnalogRead permit to read analog value from pin
+	AnalogRead(ctx context.Context, pin int) (value int, err error)
+
+	// ReadValue permit to read user variable, numbers are returned as json.Number
 	ReadValue(ctx context.Context, name string) (value interface{}, err error)
 
-	// ReadValues permit to read all user variables
+	// ReadValues permit to read all user variables, numbers are returned as json.Number
 	ReadValues(ctx context.Context) (values map[string]interface{}, err error)
 
 	// CallFunction permit to call user function
 	CallFunction(ctx context.Context, name string, param string) (resp int, err error)
 
+	// CallFunctionWithResult permit to call user function and get the full response
+	CallFunctionWithResult(ctx context.Context, name string, param string) (res *client.CallFunctionResult, err error)
+
 	// Pins perit to get current pin settings
 	Pins() map[int]*client.Pin
 
@@ -56,19 +75,158 @@ type ArestAdaptor interface {
 }
 
 // Adaptor is a general Arest Adaptor
+// It re-publish all board events, like connected, disconnected, reconnected, timeout or state-changed,
+// so robot can use them with adaptor.Event(name).
 type Adaptor struct {
 	timeout time.Duration
 	isDebug bool
-	Board   arestBoard
+	Board   Board
 	gobot.Eventer
 	name string
+
+	// It permit to stop the board events forwarding on Finalize
+	forwardMutex sync.Mutex
+	forwardStop  chan struct{}
+}
+
+// reconnectPolicySetter is implemented by boards that embed client.Protocol
+type reconnectPolicySetter interface {
+	SetReconnectPolicy(policy *client.ReconnectPolicy)
 }
 
-// Connect init connection throught HTTP to the board
+// NewAdaptorWithBoard returns a new Arest Adaptor on top of any Board implementation, which optionally accepts:
+//
+//	string: The board name
+//	time.Duration: The timeout kept on adaptor, the board is already built so it keep its own timeout
+//	bool: The debug mode kept on adaptor, the board keep its own debug mode
+//	client.ReconnectPolicy: The policy used to reconnect when connection is lost, if board support it
+func NewAdaptorWithBoard(board Board, args ...interface{}) *Adaptor {
+	a := &Adaptor{
+		name:    gobot.DefaultName("Arest"),
+		isDebug: false,
+		timeout: 0,
+		Eventer: gobot.NewEventer(),
+	}
+	a.setBoard(board)
+
+	for _, arg := range args {
+		switch argTmp := arg.(type) {
+		case string:
+			a.name = argTmp
+		case time.Duration:
+			a.timeout = argTmp
+		case bool:
+			a.isDebug = argTmp
+		case client.ReconnectPolicy:
+			a.setReconnectPolicy(&argTmp)
+		}
+	}
+
+	return a
+}
+
+// setBoard permit to set the board and re-publish all its events on adaptor
+// The error and command events are always added, even if board not publish them.
+func (a *Adaptor) setBoard(board Board) {
+	a.Board = board
+
+	for _, name := range []string{"error", "command"} {
+		a.AddEvent(name)
+	}
+	for name := range board.Events() {
+		a.AddEvent(name)
+	}
+
+	a.startForward()
+}
+
+// startForward run the routines that re-publish board events on adaptor, if not yet running
+// Events are forwarded without blocking the board, so adaptor event handlers can send commands.
+// They are dropped when client.EventQueueSize events are waiting.
+func (a *Adaptor) startForward() {
+	a.forwardMutex.Lock()
+	defer a.forwardMutex.Unlock()
+	if a.Board == nil || a.forwardStop != nil {
+		return
+	}
+
+	board := a.Board
+	events := board.Subscribe()
+	queue := make(chan *gobot.Event, client.EventQueueSize)
+	stop := make(chan struct{})
+	a.forwardStop = stop
+	go func() {
+		for {
+			select {
+			case <-stop:
+				// Board eventer can wait on full channel while it hold the lock needed by Unsubscribe
+				isUnsubscribed := make(chan struct{})
+				go func() {
+					board.Unsubscribe(events)
+					close(isUnsubscribed)
+				}()
+				for {
+					select {
+					case <-events:
+					case <-isUnsubscribed:
+						return
+					}
+				}
+			case evt := <-events:
+				select {
+				case queue <- evt:
+				default:
+					log.Warnf("Drop %s event from board, the event queue is full", evt.Name)
+				}
+			}
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case evt := <-queue:
+				a.Publish(evt.Name, evt.Data)
+			}
+		}
+	}()
+}
+
+// stopForward stop the routines that re-publish board events on adaptor
+func (a *Adaptor) stopForward() {
+	a.forwardMutex.Lock()
+	defer a.forwardMutex.Unlock()
+	if a.forwardStop != nil {
+		close(a.forwardStop)
+		a.forwardStop = nil
+	}
+}
+
+// setReconnectPolicy permit to set the reconnect policy on board if it support it
+func (a *Adaptor) setReconnectPolicy(policy *client.ReconnectPolicy) {
+	if board, ok := a.Board.(reconnectPolicySetter); ok {
+		board.SetReconnectPolicy(policy)
+	}
+}
+
+// Connect init connection to the board
+// It return client.BoardMismatchError if the board identity not match the expected one
 func (a *Adaptor) Connect() (err error) {
+	// Forwarding is stopped by Finalize
+	a.startForward()
+
 	return a.Board.Connect(context.TODO())
 }
 
+// State return the connection state of the board
+func (a *Adaptor) State() client.State {
+	if a.Board == nil {
+		return client.StateDisconnected
+	}
+	return a.Board.State()
+}
+
 // Disconnect close the connection to the Board
 func (a *Adaptor) Disconnect() (err error) {
 	if a.Board != nil {
@@ -78,15 +236,24 @@ func (a *Adaptor) Disconnect() (err error) {
 }
 
 // Finalize terminates the Arest connection
+// It stop to re-publish board events, Connect start it again.
 func (a *Adaptor) Finalize() (err error) {
+	defer a.stopForward()
+
 	return a.Disconnect()
 }
 
 // Reconnect permit to reopen connection to the board
+// It return client.BoardMismatchError if the board identity not match the expected one
 func (a *Adaptor) Reconnect() (err error) {
 	return a.Board.Reconnect(context.TODO())
 }
 
+// Info returns the identity of the board
+func (a *Adaptor) Info() (info *client.BoardInfo, err error) {
+	return a.Board.Info(context.TODO())
+}
+
 // Name returns the Arest Adaptors name
 func (a *Adaptor) Name() string {
 	return a.name
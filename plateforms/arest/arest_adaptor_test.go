@@ -3,20 +3,28 @@ package arest
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/disaster37/gobot-arest/drivers/extra"
 	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
 	"gobot.io/x/gobot/drivers/gpio"
 	"gobot.io/x/gobot/gobottest"
 )
 
 // make sure that this Adaptor fullfills all the required interfaces
+var _ Board = (*mockArestBoard)(nil)
 var _ gobot.Adaptor = (*Adaptor)(nil)
 var _ gpio.DigitalReader = (*Adaptor)(nil)
 var _ gpio.DigitalWriter = (*Adaptor)(nil)
+var _ gpio.PwmWriter = (*Adaptor)(nil)
+var _ gpio.ServoWriter = (*Adaptor)(nil)
+var _ aio.AnalogReader = (*Adaptor)(nil)
 var _ ArestAdaptor = (*Adaptor)(nil)
 var _ extra.ExtraReader = (*Adaptor)(nil)
 
@@ -55,6 +63,100 @@ func TestAdaptorConnect(t *testing.T) {
 	gobottest.Assert(t, a.Reconnect(), nil)
 }
 
+func TestAdaptorState(t *testing.T) {
+	a := initTestAdaptor()
+	gobottest.Assert(t, a.State(), client.StateConnected)
+
+	// Without board
+	a.Board = nil
+	gobottest.Assert(t, a.State(), client.StateDisconnected)
+
+	// From client
+	a = NewHTTPAdaptor("http://localhost")
+	gobottest.Assert(t, a.State(), client.StateDisconnected)
+}
+
+func TestAdaptorEvents(t *testing.T) {
+	board := newMockArestBoard()
+	board.AddEvent("reconnected")
+	a := NewAdaptorWithBoard(board)
+	gobottest.Assert(t, a.Event("reconnected"), "reconnected")
+	gobottest.Assert(t, a.Event("error"), "error")
+	gobottest.Assert(t, a.Event("command"), "command")
+
+	sem := make(chan interface{}, 1)
+	_ = a.On(a.Event("reconnected"), func(data interface{}) {
+		sem <- data
+	})
+	board.Publish("reconnected", true)
+	select {
+	case data := <-sem:
+		gobottest.Assert(t, data, true)
+	case <-time.After(1 * time.Second):
+		t.Errorf("reconnected event was not forwarded")
+	}
+
+	// Not forwarded after Finalize, until next Connect
+	gobottest.Assert(t, a.Finalize(), nil)
+	board.Publish("reconnected", true)
+	select {
+	case <-sem:
+		t.Errorf("reconnected event was forwarded after finalize")
+	case <-time.After(100 * time.Millisecond):
+	}
+	gobottest.Assert(t, a.Connect(), nil)
+	board.Publish("reconnected", false)
+	select {
+	case data := <-sem:
+		gobottest.Assert(t, data, false)
+	case <-time.After(1 * time.Second):
+		t.Errorf("reconnected event was not forwarded after connect")
+	}
+
+	// All client events
+	a = NewHTTPAdaptor("http://localhost")
+	for _, name := range []string{"connected", "disconnected", "reconnected", "timeout", "board-mismatch", "give-up", "state-changed", "command", "error"} {
+		gobottest.Assert(t, a.Event(name), name)
+	}
+}
+
+func TestAdaptorEventsHandlerCommands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"return_value": 1, "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`))
+	}))
+	defer server.Close()
+
+	// Handler send commands, it must not block the events
+	a := NewHTTPAdaptor(server.URL)
+	done := make(chan error, 1)
+	_ = a.On(a.Event("connected"), func(data interface{}) {
+		for i := 0; i < 100; i++ {
+			if _, err := a.AnalogRead("1"); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	})
+	gobottest.Assert(t, a.Connect(), nil)
+
+	select {
+	case err := <-done:
+		gobottest.Assert(t, err, nil)
+	case <-time.After(5 * time.Second):
+		t.Fatal("commands from event handler are blocked")
+	}
+}
+
+func TestAdaptorInfo(t *testing.T) {
+	a := initTestAdaptor()
+	info, err := a.Info()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, info.ID, "002")
+	gobottest.Assert(t, info.Name, "TFP")
+	gobottest.Assert(t, info.Hardware, "arduino")
+}
+
 func TestAdaptorDigitalWrite(t *testing.T) {
 	a := initTestAdaptor()
 	gobottest.Assert(t, a.DigitalWrite("1", 1), nil)
@@ -79,6 +181,36 @@ func TestAdaptorDigitalReadBadPin(t *testing.T) {
 	gobottest.Refute(t, err, nil)
 }
 
+func TestAdaptorPwmWrite(t *testing.T) {
+	a := initTestAdaptor()
+	gobottest.Assert(t, a.PwmWrite("1", 127), nil)
+	gobottest.Refute(t, a.PwmWrite("xyz", 127), nil)
+}
+
+func TestAdaptorServoWrite(t *testing.T) {
+	a := initTestAdaptor()
+	gobottest.Assert(t, a.ServoWrite("1", 90), nil)
+	gobottest.Refute(t, a.ServoWrite("xyz", 90), nil)
+}
+
+func TestAdaptorAnalogRead(t *testing.T) {
+	a := initTestAdaptor()
+
+	val, err := a.AnalogRead("0")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 512)
+
+	val, err = a.AnalogRead("A0")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 512)
+}
+
+func TestAdaptorAnalogReadBadPin(t *testing.T) {
+	a := initTestAdaptor()
+	_, err := a.AnalogRead("xyz")
+	gobottest.Refute(t, err, nil)
+}
+
 func TestAdaptorSetPinMode(t *testing.T) {
 	a := initTestAdaptor()
 
@@ -93,6 +225,29 @@ func TestAdaptorValueRead(t *testing.T) {
 	gobottest.Assert(t, value, 10)
 }
 
+func TestAdaptorReadTyped(t *testing.T) {
+	a := initTestAdaptor()
+
+	i, err := a.ReadInt("test")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, i, 10)
+
+	f, err := a.ReadFloat("test")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, f, float64(10))
+
+	s, err := a.ReadString("test")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, s, "10")
+
+	d, err := a.ReadDuration("test", time.Second)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, d, 10*time.Second)
+
+	_, err = a.ReadBool("test")
+	gobottest.Refute(t, err, nil)
+}
+
 func TestAdaptorValuesRead(t *testing.T) {
 	a := initTestAdaptor()
 	expected := map[string]interface{}{
@@ -103,6 +258,18 @@ func TestAdaptorValuesRead(t *testing.T) {
 	gobottest.Assert(t, values, expected)
 }
 
+func TestAdaptorValuesReadInto(t *testing.T) {
+	a := initTestAdaptor()
+
+	type board struct {
+		Test    int    `arest:"test"`
+		Missing string `arest:"missing,optional"`
+	}
+	b := &board{}
+	gobottest.Assert(t, a.ValuesReadInto(b), nil)
+	gobottest.Assert(t, b.Test, 10)
+}
+
 func TestAdaptorFunctionCall(t *testing.T) {
 	a := initTestAdaptor()
 
@@ -110,3 +277,12 @@ func TestAdaptorFunctionCall(t *testing.T) {
 	gobottest.Assert(t, err, nil)
 	gobottest.Assert(t, value, 0)
 }
+
+func TestAdaptorFunctionCallWithResult(t *testing.T) {
+	a := initTestAdaptor()
+
+	res, err := a.FunctionCallWithResult("test", "param1")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, res.ReturnValue, 0)
+	gobottest.Assert(t, res.Message, "Function test called")
+}
@@ -0,0 +1,338 @@
+package coapClient
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// RFC7252 transmission parameters
+const (
+	DefaultAckTimeout    = 2 * time.Second
+	DefaultMaxRetransmit = 4
+	ackRandomFactor      = 1.5
+)
+
+// DefaultTimeout is the timeout to wait the board response with default retransmission settings,
+// it's RFC7252 MAX_TRANSMIT_WAIT. When no timeout is provided, the timeout is computed from retransmission settings.
+const DefaultTimeout = 93 * time.Second
+
+// maxMessageSize is the max UDP datagram read from board
+const maxMessageSize = 1152
+
+// Client implement arest interface over CoAP.
+// Commands are sent as confirmable GET to read and POST to write, and they are retransmitted
+// with exponential backoff until the board acknowledge them.
+type Client struct {
+	address string
+	conn    *net.UDPConn
+	timeout time.Duration
+	isDebug bool
+	mutex   sync.Mutex
+
+	ackTimeout    time.Duration
+	maxRetransmit int
+	messageID     uint16
+
+	*client.Protocol
+}
+
+// NewClient permit to initialize new client Object
+// The address is the board address, like 192.168.1.10:5683 or [::1]:5683
+// The timeout cap the retransmissions, 0 permit to wait all retransmissions (MAX_TRANSMIT_WAIT).
+func NewClient(address string, timeout time.Duration, isDebug bool) *Client {
+	clientArest := &Client{
+		address:       address,
+		timeout:       timeout,
+		isDebug:       isDebug,
+		mutex:         sync.Mutex{},
+		ackTimeout:    DefaultAckTimeout,
+		maxRetransmit: DefaultMaxRetransmit,
+		messageID:     uint16(randomInt(1 << 16)),
+	}
+	clientArest.Protocol = client.NewProtocol(clientArest, isDebug)
+
+	return clientArest
+}
+
+// SetRetransmission permit to change the initial ACK timeout and the max number of retransmissions
+func (c *Client) SetRetransmission(ackTimeout time.Duration, maxRetransmit int) {
+	c.ackTimeout = ackTimeout
+	c.maxRetransmit = maxRetransmit
+}
+
+// Timeout return the max time to wait the board response, including retransmissions
+func (c *Client) Timeout() time.Duration {
+	if c.timeout > 0 {
+		return c.timeout
+	}
+
+	return maxTransmitWait(c.ackTimeout, c.maxRetransmit)
+}
+
+// maxTransmitWait return RFC7252 MAX_TRANSMIT_WAIT, the max time from first transmission to the last response
+func maxTransmitWait(ackTimeout time.Duration, maxRetransmit int) time.Duration {
+	return time.Duration(float64(ackTimeout) * float64(int64(1)<<(maxRetransmit+1)-1) * ackRandomFactor)
+}
+
+// Client permit to get curent UDP connection
+func (c *Client) Client() *net.UDPConn {
+	return c.conn
+}
+
+// Address return the board address
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Send permit to send command to the board throught CoAP
+// It implement client.Transport interface
+// When the board not answer after all retransmissions, it try to reconnect with the reconnect policy.
+func (c *Client) Send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	if !c.IsConnected() {
+		return nil, client.ErrNotConnected
+	}
+
+	body, err = c.send(ctx, cmd)
+	if errors.Is(err, client.ErrTimeout) && c.IsConnected() {
+		c.handleLost(err)
+	}
+
+	return body, err
+}
+
+// handleLost mark the board as disconnected and start the reconnect routine
+func (c *Client) handleLost(err error) {
+	c.SetState(client.StateDisconnected, err.Error())
+	c.Publish("disconnected", true)
+	c.StartReconnect(c.reconnect)
+}
+
+// send permit to send confirmable request and wait the response
+// The response can be piggybacked on ACK or sent after an empty ACK.
+func (c *Client) send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.conn == nil {
+		return nil, client.ErrNotConnected
+	}
+
+	c.messageID++
+	token := make([]byte, 4)
+	if _, err = rand.Read(token); err != nil {
+		return nil, err
+	}
+	req, err := newRequest(cmd, c.messageID, token).encode()
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.Timeout())
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	// Initial ACK timeout is random between ackTimeout and ackTimeout * ackRandomFactor
+	ackTimeout := c.ackTimeout + time.Duration(randomInt(int64(float64(c.ackTimeout)*(ackRandomFactor-1))+1))
+	isAcknowledged := false
+	retransmit := 0
+	buffer := make([]byte, maxMessageSize)
+
+	for {
+		if !isAcknowledged {
+			if _, err = c.conn.Write(req); err != nil {
+				return nil, errors.Wrapf(err, "Error when send %s", cmd)
+			}
+		}
+		attemptDeadline := time.Now().Add(ackTimeout)
+		if isAcknowledged || attemptDeadline.After(deadline) {
+			attemptDeadline = deadline
+		}
+
+		// Read until the response or the attempt timeout
+		for {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+			_ = c.conn.SetReadDeadline(attemptDeadline)
+			n, err := c.conn.Read(buffer)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				return nil, errors.Wrapf(err, "Error when wait response of %s", cmd)
+			}
+
+			resp, err := decodeMessage(buffer[:n])
+			if err != nil {
+				if c.isDebug {
+					log.Debugf("Drop bad CoAP message: %s", err.Error())
+				}
+				continue
+			}
+
+			switch {
+			case resp.typ == typeReset && resp.messageID == c.messageID:
+				return nil, errors.Wrapf(client.ErrProtocol, "Board reset request %s", cmd)
+			case resp.typ == typeAcknowledgement && resp.messageID == c.messageID && resp.code == codeEmpty:
+				// Separate response will follow
+				isAcknowledged = true
+				attemptDeadline = deadline
+				continue
+			case string(resp.token) != string(token) || resp.code == codeEmpty:
+				if c.isDebug {
+					log.Debugf("Drop CoAP message %d without pending request", resp.messageID)
+				}
+				continue
+			}
+
+			if resp.typ == typeConfirmable {
+				// Acknowledge separate response
+				ack, _ := (&message{typ: typeAcknowledgement, code: codeEmpty, messageID: resp.messageID}).encode()
+				if _, err = c.conn.Write(ack); err != nil {
+					return nil, errors.Wrapf(err, "Error when acknowledge response of %s", cmd)
+				}
+			}
+
+			if !isSuccess(resp.code) {
+				return nil, errors.Wrapf(client.ErrProtocol, "Board return %s on %s: %s", codeString(resp.code), cmd, string(resp.payload))
+			}
+
+			return resp.payload, nil
+		}
+
+		if !time.Now().Before(deadline) || isAcknowledged || retransmit >= c.maxRetransmit {
+			c.Publish("timeout", true)
+			return nil, errors.Wrapf(client.ErrTimeout, "No response of %s", cmd)
+		}
+		retransmit++
+		ackTimeout *= 2
+		if c.isDebug {
+			log.Debugf("Retransmit %s (%d/%d)", cmd, retransmit, c.maxRetransmit)
+		}
+	}
+}
+
+// randomInt return random int between 0 and max
+func randomInt(max int64) int64 {
+	n, err := rand.Int(rand.Reader, big.NewInt(max))
+	if err != nil {
+		return 0
+	}
+	return n.Int64()
+}
+
+// Connect start connection to the board
+// It call the root url to check if board is online
+func (c *Client) Connect(ctx context.Context) (err error) {
+
+	if c.IsConnected() {
+		return
+	}
+
+	c.SetConnecting()
+	defer func() {
+		if err != nil {
+			c.SetConnectFailed(err)
+		}
+	}()
+
+	addr, err := net.ResolveUDPAddr("udp", c.address)
+	if err != nil {
+		return errors.Wrapf(err, "Can't resolve %s", c.address)
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return errors.Wrapf(err, "Can't connect on %s", c.address)
+	}
+	c.mutex.Lock()
+	c.conn = conn
+	c.mutex.Unlock()
+
+	// Try connexion
+	// The root url is used because of it return identity and variables
+	body, err := c.send(ctx, client.RootCommand())
+	if err == nil {
+		_, err = c.HandleInfo(client.RootCommand(), body)
+	}
+	if err != nil {
+		c.mutex.Lock()
+		c.conn = nil
+		c.mutex.Unlock()
+		_ = conn.Close()
+		return err
+	}
+
+	c.Publish("connected", true)
+	c.SetState(client.StateConnected, "connected")
+
+	return nil
+}
+
+// Disconnect close connecion to the board
+// It stop the reconnect routine if any
+func (c *Client) Disconnect(ctx context.Context) (err error) {
+	c.StopReconnect()
+	c.SetState(client.StateDisconnected, "disconnect")
+
+	if err = c.disconnect(); err != nil {
+		return err
+	}
+
+	c.Publish("disconnected", true)
+
+	return nil
+}
+
+// disconnect close the UDP connection
+// The state is changed by caller.
+func (c *Client) disconnect() (err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.conn != nil {
+		if err = c.conn.Close(); err != nil {
+			return err
+		}
+		c.conn = nil
+	}
+
+	return nil
+}
+
+// Reconnect close and start connection to the board
+func (c *Client) Reconnect(ctx context.Context) (err error) {
+	err = c.Disconnect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.reconnect(ctx)
+}
+
+// reconnect start connection to the board, then restore pins
+func (c *Client) reconnect(ctx context.Context) (err error) {
+	if err = c.disconnect(); err != nil {
+		return err
+	}
+	err = c.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Set pin mode and output
+	if err = c.RestorePins(ctx); err != nil {
+		return err
+	}
+
+	c.Publish("reconnected", true)
+
+	return nil
+}
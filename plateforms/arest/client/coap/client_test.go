@@ -0,0 +1,187 @@
+package coapClient
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+)
+
+type ArestTestSuite struct {
+	suite.Suite
+	board  *mockBoard
+	client *Client
+}
+
+func (s *ArestTestSuite) SetupSuite() {
+	// Init logger
+	logrus.SetFormatter(new(prefixed.TextFormatter))
+	logrus.SetLevel(logrus.DebugLevel)
+}
+
+func (s *ArestTestSuite) SetupTest() {
+	s.board = newMockBoard()
+	s.client = NewClient(s.board.Address(), 2*time.Second, true)
+	s.client.SetRetransmission(20*time.Millisecond, 2)
+}
+
+func (s *ArestTestSuite) TearDownTest() {
+	_ = s.client.Disconnect(context.Background())
+	s.board.Close()
+}
+
+func TestArestTestSuite(t *testing.T) {
+	suite.Run(t, new(ArestTestSuite))
+}
+
+func (s *ArestTestSuite) TestConnect() {
+	err := s.client.Connect(context.Background())
+	assert.NoError(s.T(), err)
+
+	info, err := s.client.Info(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "esp32", info.Hardware)
+	assert.Equal(s.T(), s.board.Address(), s.client.Address())
+
+	// Board not reachable
+	c := NewClient("127.0.0.1:1", 500*time.Millisecond, false)
+	c.SetRetransmission(20*time.Millisecond, 1)
+	assert.Error(s.T(), c.Connect(context.Background()))
+}
+
+func (s *ArestTestSuite) TestDigital() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 3, client.ModeOutput))
+	assert.NoError(s.T(), s.client.DigitalWrite(context.Background(), 3, client.LevelHigh))
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 4, client.ModeInput))
+	level, err := s.client.DigitalRead(context.Background(), 4)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), client.LevelHigh, level)
+
+	assert.Equal(s.T(), []string{"GET /", "POST /mode/3/o", "POST /digital/3/1", "POST /mode/4/i", "GET /digital/4"}, s.board.Requests())
+
+	// Resource not found
+	_, err = s.client.ReadValue(context.Background(), "unknown")
+	assert.ErrorIs(s.T(), err, client.ErrProtocol)
+}
+
+func (s *ArestTestSuite) TestRetransmission() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Answer after one retransmission
+	s.board.Drop(1)
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 4, client.ModeInput))
+	assert.Equal(s.T(), []string{"GET /", "POST /mode/4/i", "POST /mode/4/i"}, s.board.Requests())
+
+	// No answer after all retransmissions
+	isTimeout := make(chan bool, 1)
+	if err := s.client.On("timeout", func(data interface{}) {
+		isTimeout <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+	s.board.Drop(3)
+	_, err := s.client.DigitalRead(context.Background(), 4)
+	assert.ErrorIs(s.T(), err, client.ErrTimeout)
+	assert.Len(s.T(), s.board.Requests(), 6)
+	select {
+	case <-isTimeout:
+	case <-time.After(time.Second):
+		s.T().Error("timeout event not published")
+	}
+}
+
+func (s *ArestTestSuite) TestTimeout() {
+	// Wait all retransmissions by default
+	assert.Equal(s.T(), DefaultTimeout, NewClient(s.board.Address(), 0, false).Timeout())
+	c := NewClient(s.board.Address(), 0, false)
+	c.SetRetransmission(20*time.Millisecond, 2)
+	assert.Equal(s.T(), 210*time.Millisecond, c.Timeout())
+
+	// Timeout cap retransmissions
+	assert.Equal(s.T(), 2*time.Second, s.client.Timeout())
+}
+
+func (s *ArestTestSuite) TestSeparateResponse() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	value, err := s.client.ReadValue(context.Background(), "slow")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), json.Number("1"), value)
+
+	// Separate response is acknowledged
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(s.T(), []uint16{1000}, s.board.Acks())
+}
+
+func (s *ArestTestSuite) TestReconnect() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.DigitalWrite(context.Background(), 3, client.LevelHigh); err != nil {
+		s.T().Fatal(err)
+	}
+
+	err := s.client.Reconnect(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"GET /", "POST /mode/3/o", "POST /digital/3/1", "GET /", "POST /mode/3/o", "POST /digital/3/1"}, s.board.Requests())
+}
+
+func (s *ArestTestSuite) TestConnectionLost() {
+	s.client.SetReconnectPolicy(&client.ReconnectPolicy{InitialInterval: 20 * time.Millisecond, MaxAttempts: 10})
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+
+	isDisconnected := make(chan bool, 1)
+	isReconnected := make(chan bool, 1)
+	if err := s.client.On("disconnected", func(data interface{}) {
+		isDisconnected <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.On("reconnected", func(data interface{}) {
+		isReconnected <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Board not answer after all retransmissions
+	s.board.Drop(3)
+	_, err := s.client.ReadValue(context.Background(), "temperature")
+	assert.ErrorIs(s.T(), err, client.ErrTimeout)
+	select {
+	case <-isDisconnected:
+	case <-time.After(time.Second):
+		s.T().Fatal("disconnected event not published")
+	}
+	select {
+	case <-isReconnected:
+	case <-time.After(2 * time.Second):
+		s.T().Fatal("reconnected event not published")
+	}
+
+	// Pins are restored
+	assert.Equal(s.T(), client.StateConnected, s.client.State())
+	assert.Equal(s.T(), []string{"GET /", "POST /mode/3/o", "GET /temperature", "GET /temperature", "GET /temperature", "GET /", "POST /mode/3/o", "POST /digital/3/0"}, s.board.Requests())
+}
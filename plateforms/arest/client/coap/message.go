@@ -0,0 +1,238 @@
+package coapClient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/pkg/errors"
+)
+
+// RFC7252 message types
+const (
+	typeConfirmable     uint8 = 0
+	typeNonConfirmable  uint8 = 1
+	typeAcknowledgement uint8 = 2
+	typeReset           uint8 = 3
+)
+
+// RFC7252 codes, class on 3 bits and detail on 5 bits
+const (
+	codeEmpty    uint8 = 0x00
+	codeGet      uint8 = 0x01
+	codePost     uint8 = 0x02
+	codeChanged  uint8 = 0x44
+	codeContent  uint8 = 0x45
+	codeNotFound uint8 = 0x84
+)
+
+// RFC7252 options used by aREST resources
+const (
+	optionURIPath       uint16 = 11
+	optionContentFormat uint16 = 12
+	optionURIQuery      uint16 = 15
+)
+
+const (
+	coapVersion   uint8 = 1
+	payloadMarker byte  = 0xFF
+)
+
+// option is a CoAP option
+type option struct {
+	number uint16
+	value  []byte
+}
+
+// message is a CoAP message
+type message struct {
+	typ       uint8
+	code      uint8
+	messageID uint16
+	token     []byte
+	options   []option
+	payload   []byte
+}
+
+// codeString return the code like 2.05
+func codeString(code uint8) string {
+	return fmt.Sprintf("%d.%02d", code>>5, code&0x1F)
+}
+
+// isSuccess return true if code is on 2.xx class
+func isSuccess(code uint8) bool {
+	return code>>5 == 2
+}
+
+// newRequest return the CoAP request for aREST command
+// GET is used to read and POST to write, the path and the query are sent as options.
+func newRequest(cmd *client.Command, messageID uint16, token []byte) *message {
+	m := &message{
+		typ:       typeConfirmable,
+		code:      codeGet,
+		messageID: messageID,
+		token:     token,
+		options:   make([]option, 0),
+	}
+	if cmd.Method == http.MethodPost {
+		m.code = codePost
+	}
+
+	for _, segment := range strings.Split(cmd.Path, "/") {
+		if segment != "" {
+			m.options = append(m.options, option{number: optionURIPath, value: []byte(segment)})
+		}
+	}
+	if cmd.Query != "" {
+		for _, query := range strings.Split(cmd.Query, "&") {
+			m.options = append(m.options, option{number: optionURIQuery, value: []byte(query)})
+		}
+	}
+
+	return m
+}
+
+// path return the request line, like /test?params=on
+func (m *message) path() string {
+	segments := make([]string, 0)
+	queries := make([]string, 0)
+	for _, o := range m.options {
+		switch o.number {
+		case optionURIPath:
+			segments = append(segments, string(o.value))
+		case optionURIQuery:
+			queries = append(queries, string(o.value))
+		}
+	}
+
+	path := "/" + strings.Join(segments, "/")
+	if len(queries) > 0 {
+		path = fmt.Sprintf("%s?%s", path, strings.Join(queries, "&"))
+	}
+
+	return path
+}
+
+// encode return the message on wire format
+func (m *message) encode() ([]byte, error) {
+	if len(m.token) > 8 {
+		return nil, errors.Errorf("Token too long: %d bytes", len(m.token))
+	}
+
+	b := make([]byte, 4, 4+len(m.token)+len(m.payload)+16)
+	b[0] = coapVersion<<6 | m.typ<<4 | uint8(len(m.token))
+	b[1] = m.code
+	binary.BigEndian.PutUint16(b[2:], m.messageID)
+	b = append(b, m.token...)
+
+	options := append([]option{}, m.options...)
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].number < options[j].number
+	})
+	var previous uint16
+	for _, o := range options {
+		delta, deltaExt := encodeOptionNibble(int(o.number - previous))
+		length, lengthExt := encodeOptionNibble(len(o.value))
+		b = append(b, delta<<4|length)
+		b = append(b, deltaExt...)
+		b = append(b, lengthExt...)
+		b = append(b, o.value...)
+		previous = o.number
+	}
+
+	if len(m.payload) > 0 {
+		b = append(b, payloadMarker)
+		b = append(b, m.payload...)
+	}
+
+	return b, nil
+}
+
+// encodeOptionNibble return the 4 bits value and its extended bytes
+func encodeOptionNibble(v int) (nibble byte, ext []byte) {
+	switch {
+	case v < 13:
+		return byte(v), nil
+	case v < 269:
+		return 13, []byte{byte(v - 13)}
+	default:
+		ext = make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(v-269))
+		return 14, ext
+	}
+}
+
+// decodeMessage read message from wire format
+func decodeMessage(b []byte) (m *message, err error) {
+	if len(b) < 4 {
+		return nil, errors.Errorf("Message too short: %d bytes", len(b))
+	}
+	if b[0]>>6 != coapVersion {
+		return nil, errors.Errorf("Bad CoAP version %d", b[0]>>6)
+	}
+	tkl := int(b[0] & 0x0F)
+	if tkl > 8 || len(b) < 4+tkl {
+		return nil, errors.Errorf("Bad token length %d", tkl)
+	}
+
+	m = &message{
+		typ:       (b[0] >> 4) & 0x03,
+		code:      b[1],
+		messageID: binary.BigEndian.Uint16(b[2:]),
+		token:     append([]byte{}, b[4:4+tkl]...),
+		options:   make([]option, 0),
+	}
+
+	b = b[4+tkl:]
+	var number int
+	for len(b) > 0 {
+		if b[0] == payloadMarker {
+			if len(b) == 1 {
+				return nil, errors.New("Payload marker without payload")
+			}
+			m.payload = append([]byte{}, b[1:]...)
+			break
+		}
+
+		header := b[0]
+		var delta, length int
+		if delta, b, err = decodeOptionNibble(header>>4, b[1:]); err != nil {
+			return nil, err
+		}
+		if length, b, err = decodeOptionNibble(header&0x0F, b); err != nil {
+			return nil, err
+		}
+		if len(b) < length {
+			return nil, errors.Errorf("Option length %d exceed message", length)
+		}
+
+		number += delta
+		m.options = append(m.options, option{number: uint16(number), value: append([]byte{}, b[:length]...)})
+		b = b[length:]
+	}
+
+	return m, nil
+}
+
+// decodeOptionNibble return the value from 4 bits value and its extended bytes
+func decodeOptionNibble(nibble byte, b []byte) (v int, rest []byte, err error) {
+	switch nibble {
+	case 13:
+		if len(b) < 1 {
+			return 0, nil, errors.New("Option extended value exceed message")
+		}
+		return int(b[0]) + 13, b[1:], nil
+	case 14:
+		if len(b) < 2 {
+			return 0, nil, errors.New("Option extended value exceed message")
+		}
+		return int(binary.BigEndian.Uint16(b)) + 269, b[2:], nil
+	case 15:
+		return 0, nil, errors.New("Reserved option nibble 15")
+	default:
+		return int(nibble), b, nil
+	}
+}
@@ -0,0 +1,58 @@
+package coapClient
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage(t *testing.T) {
+	cmd, err := client.FunctionCommand("test", "on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newRequest(cmd, 0x1234, []byte{1, 2})
+	b, err := m.encode()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x42, codePost, 0x12, 0x34, 1, 2,
+		// Uri-Path test
+		0xB4, 't', 'e', 's', 't',
+		// Uri-Query params=on
+		0x49, 'p', 'a', 'r', 'a', 'm', 's', '=', 'o', 'n',
+	}, b)
+
+	decoded, err := decodeMessage(b)
+	assert.NoError(t, err)
+	assert.Equal(t, m, decoded)
+	assert.Equal(t, "/test?params=on", decoded.path())
+
+	// With payload and extended option length
+	m = &message{
+		typ:       typeAcknowledgement,
+		code:      codeContent,
+		messageID: 1,
+		token:     []byte{},
+		options:   []option{{number: optionURIPath, value: []byte(strings.Repeat("a", 20))}},
+		payload:   []byte(`{"return_value": 1}`),
+	}
+	b, err = m.encode()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xBD, 7}, b[4:6])
+	decoded, err = decodeMessage(b)
+	assert.NoError(t, err)
+	assert.Equal(t, m, decoded)
+	assert.Equal(t, "2.05", codeString(decoded.code))
+
+	// Bad messages
+	_, err = decodeMessage([]byte{0x40, 0x01})
+	assert.Error(t, err)
+	_, err = decodeMessage([]byte{0x80, 0x01, 0, 0})
+	assert.Error(t, err)
+	_, err = decodeMessage([]byte{0x40, 0x01, 0, 0, 0xB5, 'a'})
+	assert.Error(t, err)
+	_, err = decodeMessage([]byte{0x40, 0x01, 0, 0, 0xFF})
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package coapClient
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mockBoard is a fake aREST board listening on CoAP
+type mockBoard struct {
+	conn *net.UDPConn
+	// requests is the received requests, like GET /digital/3
+	requests []string
+	// drop is the number of next messages not answered
+	drop  int
+	acks  []uint16
+	mutex sync.Mutex
+}
+
+func newMockBoard() *mockBoard {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		panic(err)
+	}
+	m := &mockBoard{
+		conn:     conn,
+		requests: make([]string, 0),
+		acks:     make([]uint16, 0),
+	}
+
+	go m.handle()
+
+	return m
+}
+
+func (m *mockBoard) Address() string {
+	return m.conn.LocalAddr().String()
+}
+
+func (m *mockBoard) Close() {
+	_ = m.conn.Close()
+}
+
+func (m *mockBoard) Drop(n int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.drop = n
+}
+
+func (m *mockBoard) Requests() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string{}, m.requests...)
+}
+
+func (m *mockBoard) Acks() []uint16 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]uint16{}, m.acks...)
+}
+
+func (m *mockBoard) handle() {
+	buffer := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := m.conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+		req, err := decodeMessage(buffer[:n])
+		if err != nil {
+			continue
+		}
+
+		m.mutex.Lock()
+		if req.typ == typeAcknowledgement {
+			m.acks = append(m.acks, req.messageID)
+			m.mutex.Unlock()
+			continue
+		}
+		method := "GET"
+		if req.code == codePost {
+			method = "POST"
+		}
+		m.requests = append(m.requests, fmt.Sprintf("%s %s", method, req.path()))
+		isDropped := m.drop > 0
+		if isDropped {
+			m.drop--
+		}
+		m.mutex.Unlock()
+		if isDropped {
+			continue
+		}
+
+		code, payload := m.response(req.path())
+		resp := &message{
+			typ:       typeAcknowledgement,
+			code:      code,
+			messageID: req.messageID,
+			token:     req.token,
+			payload:   []byte(payload),
+		}
+
+		if req.path() == "/slow" {
+			// Separate response
+			ack, _ := (&message{typ: typeAcknowledgement, code: codeEmpty, messageID: req.messageID}).encode()
+			_, _ = m.conn.WriteToUDP(ack, addr)
+			time.Sleep(50 * time.Millisecond)
+			resp.typ = typeConfirmable
+			resp.messageID = 1000
+		}
+
+		b, _ := resp.encode()
+		_, _ = m.conn.WriteToUDP(b, addr)
+	}
+}
+
+func (m *mockBoard) response(path string) (code uint8, payload string) {
+	switch {
+	case path == "/":
+		return codeContent, `{"variables": {"isRebooted": false}, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`
+	case path == "/slow":
+		return codeContent, `{"slow": 1, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`
+	case path == "/unknown":
+		return codeNotFound, "Not found"
+	case strings.HasPrefix(path, "/digital/") && strings.Count(path, "/") == 2:
+		return codeContent, `{"return_value": 1, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`
+	default:
+		return codeChanged, fmt.Sprintf(`{"message": "%s done", "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`, path)
+	}
+}
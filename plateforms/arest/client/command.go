@@ -0,0 +1,118 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Command represent an aREST request, independent of the transport
+type Command struct {
+	// Method is the HTTP like method, GET to read and POST to write
+	Method string
+
+	// Path is the aREST resource, like /digital/3/1
+	Path string
+
+	// Query is the encoded query string, like params=on
+	Query string
+}
+
+// String return the aREST request line, like /test?params=on
+func (c *Command) String() string {
+	if c.Query == "" {
+		return c.Path
+	}
+	return fmt.Sprintf("%s?%s", c.Path, c.Query)
+}
+
+// IDCommand return the command to read the board identity
+func IDCommand() *Command {
+	return &Command{
+		Method: http.MethodGet,
+		Path:   "/id",
+	}
+}
+
+// RootCommand return the command to read the board identity and all variables
+func RootCommand() *Command {
+	return &Command{
+		Method: http.MethodGet,
+		Path:   "/",
+	}
+}
+
+// ModeCommand return the command to set pin mode
+func ModeCommand(pin int, mode string) (cmd *Command, err error) {
+	if mode != ModeInput && mode != ModeInputPullup && mode != ModeOutput {
+		return nil, &PinError{Pin: pin, Mode: mode, Err: ErrWrongPinMode}
+	}
+
+	return &Command{
+		Method: http.MethodPost,
+		Path:   fmt.Sprintf("/mode/%d/%s", pin, mode),
+	}, nil
+}
+
+// DigitalWriteCommand return the command to set level on pin
+func DigitalWriteCommand(pin int, level int) (cmd *Command, err error) {
+	if level != LevelHigh && level != LevelLow {
+		return nil, errors.Errorf("Can't found level %d", level)
+	}
+
+	return &Command{
+		Method: http.MethodPost,
+		Path:   fmt.Sprintf("/digital/%d/%d", pin, level),
+	}, nil
+}
+
+// DigitalReadCommand return the command to read level from pin
+func DigitalReadCommand(pin int) *Command {
+	return &Command{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/digital/%d", pin),
+	}
+}
+
+// AnalogWriteCommand return the command to write PWM value on pin
+func AnalogWriteCommand(pin int, value int) (cmd *Command, err error) {
+	if value < DutyMin || value > DutyMax {
+		return nil, errors.Errorf("Value %d is out of range [%d, %d]", value, DutyMin, DutyMax)
+	}
+
+	return &Command{
+		Method: http.MethodPost,
+		Path:   fmt.Sprintf("/analog/%d/%d", pin, value),
+	}, nil
+}
+
+// AnalogReadCommand return the command to read analog value from pin
+func AnalogReadCommand(pin int) *Command {
+	return &Command{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/analog/%d", pin),
+	}
+}
+
+// VariableCommand return the command to read user variable
+func VariableCommand(name string) *Command {
+	return &Command{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/%s", name),
+	}
+}
+
+// FunctionCommand return the command to call user function
+func FunctionCommand(name string, params string) (cmd *Command, err error) {
+	encodedParams, err := EncodeParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Command{
+		Method: http.MethodPost,
+		Path:   fmt.Sprintf("/%s", name),
+		Query:  fmt.Sprintf("params=%s", encodedParams),
+	}, nil
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommands(t *testing.T) {
+
+	assert.Equal(t, "/id", IDCommand().String())
+	assert.Equal(t, "/", RootCommand().String())
+
+	cmd, err := ModeCommand(3, ModeOutput)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, cmd.Method)
+	assert.Equal(t, "/mode/3/o", cmd.String())
+	_, err = ModeCommand(3, "bad")
+	assert.Error(t, err)
+
+	cmd, err = DigitalWriteCommand(3, LevelHigh)
+	assert.NoError(t, err)
+	assert.Equal(t, "/digital/3/1", cmd.String())
+	_, err = DigitalWriteCommand(3, 2)
+	assert.Error(t, err)
+
+	cmd = DigitalReadCommand(3)
+	assert.Equal(t, http.MethodGet, cmd.Method)
+	assert.Equal(t, "/digital/3", cmd.String())
+
+	cmd, err = AnalogWriteCommand(3, 127)
+	assert.NoError(t, err)
+	assert.Equal(t, "/analog/3/127", cmd.String())
+	_, err = AnalogWriteCommand(3, 256)
+	assert.Error(t, err)
+
+	assert.Equal(t, "/analog/0", AnalogReadCommand(0).String())
+	assert.Equal(t, "/temperature", VariableCommand("temperature").String())
+
+	cmd, err = FunctionCommand("pump", "on 12")
+	assert.NoError(t, err)
+	assert.Equal(t, "/pump", cmd.Path)
+	assert.Equal(t, "params=on+12", cmd.Query)
+	assert.Equal(t, "/pump?params=on+12", cmd.String())
+}
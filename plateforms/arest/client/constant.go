@@ -14,3 +14,9 @@ const LevelHigh = 1
 
 // LevelLow permit to set output with low level
 const LevelLow = 0
+
+// DutyMin is the minimal PWM value
+const DutyMin = 0
+
+// DutyMax is the maximal PWM value
+const DutyMax = 255
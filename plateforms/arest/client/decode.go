@@ -0,0 +1,251 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDecodeErrorBodyLength is the max body length kept on DecodeError
+const maxDecodeErrorBodyLength = 256
+
+// DecodeError is returned when the board response is not the expected aREST response.
+// It wrap ErrProtocol.
+type DecodeError struct {
+	// Command is the aREST request line that produced the response, like /digital/3
+	Command string
+
+	// Field is the JSON field that can't be decoded, empty if the body is not a JSON object
+	Field string
+
+	// Body is the raw response, truncated
+	Body string
+
+	// Reason explain why the response can't be decoded
+	Reason string
+}
+
+// Error implement error interface
+func (e *DecodeError) Error() string {
+	var msg strings.Builder
+	msg.WriteString("Can't decode ")
+	if e.Field != "" {
+		fmt.Fprintf(&msg, "field %s of ", e.Field)
+	}
+	msg.WriteString("response")
+	if e.Command != "" {
+		fmt.Fprintf(&msg, " of %s", e.Command)
+	}
+	fmt.Fprintf(&msg, ": %s, body: %q", e.Reason, e.Body)
+
+	return msg.String()
+}
+
+// Unwrap permit to use errors.Is with ErrProtocol
+func (e *DecodeError) Unwrap() error {
+	return ErrProtocol
+}
+
+// response is a decoded aREST JSON object
+// Numbers are kept as json.Number to not lose precision.
+type response struct {
+	command string
+	body    []byte
+	data    map[string]interface{}
+}
+
+// decodeResponse permit to decode aREST response, it must be a single JSON object
+func decodeResponse(command string, body []byte) (r *response, err error) {
+	r = &response{
+		command: command,
+		body:    body,
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data interface{}
+	if err = decoder.Decode(&data); err != nil {
+		return nil, r.error("", "invalid JSON: %s", err.Error())
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, r.error("", "unexpected data after JSON object")
+	}
+
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, r.error("", "expected JSON object, got %s", typeName(data))
+	}
+	r.data = obj
+
+	return r, nil
+}
+
+// error return DecodeError for field
+func (r *response) error(field string, format string, args ...interface{}) *DecodeError {
+	body := strings.TrimSpace(string(r.body))
+	if len(body) > maxDecodeErrorBodyLength {
+		body = body[:maxDecodeErrorBodyLength] + "..."
+	}
+
+	return &DecodeError{
+		Command: r.command,
+		Field:   field,
+		Body:    body,
+		Reason:  fmt.Sprintf(format, args...),
+	}
+}
+
+// has return true if field is present
+func (r *response) has(field string) bool {
+	_, ok := r.data[field]
+	return ok
+}
+
+// int return the field as integer
+func (r *response) int(field string) (value int, err error) {
+	temp, ok := r.data[field]
+	if !ok {
+		return value, r.error(field, "field is missing")
+	}
+	number, ok := temp.(json.Number)
+	if !ok {
+		return value, r.error(field, "expected integer, got %s", typeName(temp))
+	}
+	valueTmp, err := number.Int64()
+	if err != nil {
+		// Integer can be sent as float, like 1.0
+		f, errFloat := number.Float64()
+		if errFloat != nil || f != float64(int64(f)) {
+			return value, r.error(field, "expected integer, got %s", number.String())
+		}
+		valueTmp = int64(f)
+	}
+
+	return int(valueTmp), nil
+}
+
+// string return the field as string, or empty string if field is missing
+func (r *response) string(field string) (value string, err error) {
+	temp, ok := r.data[field]
+	if !ok || temp == nil {
+		return "", nil
+	}
+	value, ok = temp.(string)
+	if !ok {
+		return "", r.error(field, "expected string, got %s", typeName(temp))
+	}
+
+	return value, nil
+}
+
+// bool return the field as bool, or false if field is missing
+func (r *response) bool(field string) (value bool, err error) {
+	temp, ok := r.data[field]
+	if !ok || temp == nil {
+		return false, nil
+	}
+	value, ok = temp.(bool)
+	if !ok {
+		return false, r.error(field, "expected boolean, got %s", typeName(temp))
+	}
+
+	return value, nil
+}
+
+// id return the board id, aREST firmware can send it as string or number
+func (r *response) id() (value string, err error) {
+	temp, ok := r.data["id"]
+	if !ok || temp == nil {
+		return "", nil
+	}
+	switch v := temp.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", r.error("id", "expected string or number, got %s", typeName(temp))
+	}
+}
+
+// object return the field as JSON object
+func (r *response) object(field string) (value map[string]interface{}, err error) {
+	temp, ok := r.data[field]
+	if !ok {
+		return nil, r.error(field, "field is missing")
+	}
+	value, ok = temp.(map[string]interface{})
+	if !ok {
+		return nil, r.error(field, "expected object, got %s", typeName(temp))
+	}
+
+	return value, nil
+}
+
+// typeName return the JSON type name of decoded value
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// DecodeAck permit to decode aREST response of write command and return the board message
+func DecodeAck(cmd *Command, body []byte) (message string, err error) {
+	r, err := decodeResponse(cmd.String(), body)
+	if err != nil {
+		return "", err
+	}
+
+	return r.string("message")
+}
+
+// DecodeReturnValue permit to decode the return_value from aREST response
+func DecodeReturnValue(cmd *Command, body []byte) (value int, err error) {
+	r, err := decodeResponse(cmd.String(), body)
+	if err != nil {
+		return value, err
+	}
+
+	return r.int("return_value")
+}
+
+// DecodeVariable permit to decode user variable from aREST response
+// Numbers are returned as json.Number.
+func DecodeVariable(name string, body []byte) (value interface{}, err error) {
+	r, err := decodeResponse(VariableCommand(name).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.has(name) {
+		return nil, &VariableError{Name: name, Err: ErrVariableNotFound}
+	}
+
+	return r.data[name], nil
+}
+
+// DecodeVariables permit to decode all user variables from aREST response
+// Numbers are returned as json.Number.
+func DecodeVariables(body []byte) (values map[string]interface{}, err error) {
+	r, err := decodeResponse(RootCommand().String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.object("variables")
+}
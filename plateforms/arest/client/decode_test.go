@@ -0,0 +1,100 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeAck(t *testing.T) {
+	cmd, _ := ModeCommand(3, ModeOutput)
+
+	message, err := DecodeAck(cmd, []byte(`{"message": "Pin D3 set to output", "id": "002"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Pin D3 set to output", message)
+
+	_, err = DecodeAck(cmd, []byte(`<html></html>`))
+	assert.Error(t, err)
+}
+
+func TestDecodeReturnValue(t *testing.T) {
+	cmd := DigitalReadCommand(3)
+
+	value, err := DecodeReturnValue(cmd, []byte(`{"return_value": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	_, err = DecodeReturnValue(cmd, []byte(`{"message": "test"}`))
+	assert.Error(t, err)
+
+	_, err = DecodeReturnValue(cmd, []byte(`{"return_value": "1"}`))
+	assert.Error(t, err)
+
+	value, err = DecodeReturnValue(cmd, []byte(`{"return_value": 1.0}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestDecodeError(t *testing.T) {
+	cmd := DigitalReadCommand(3)
+	decodeErr := &DecodeError{}
+
+	// Null value
+	_, err := DecodeReturnValue(cmd, []byte(`{"return_value": null}`))
+	assert.ErrorIs(t, err, ErrProtocol)
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, "/digital/3", decodeErr.Command)
+	assert.Equal(t, "return_value", decodeErr.Field)
+	assert.Equal(t, `{"return_value": null}`, decodeErr.Body)
+	assert.Equal(t, `Can't decode field return_value of response of /digital/3: expected integer, got null, body: "{\"return_value\": null}"`, err.Error())
+
+	// Truncated line
+	_, err = DecodeReturnValue(cmd, []byte(`{"return_value": 1, "id": "0`))
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Empty(t, decodeErr.Field)
+
+	// Not an object
+	for _, body := range []string{`[1]`, `"1"`, `null`, ``, `{"return_value": 1}{"return_value": 0}`} {
+		_, err = DecodeReturnValue(cmd, []byte(body))
+		assert.ErrorAs(t, err, &decodeErr, body)
+	}
+
+	// Bad type on identity fields
+	_, err = DecodeAck(cmd, []byte(`{"message": 1}`))
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, "message", decodeErr.Field)
+	_, err = DecodeBoardInfo(IDCommand(), []byte(`{"id": "002", "connected": "yes"}`))
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, "connected", decodeErr.Field)
+	assert.Equal(t, "/id", decodeErr.Command)
+	_, err = DecodeBoardInfo(IDCommand(), []byte(`{"id": ["002"]}`))
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, "id", decodeErr.Field)
+}
+
+func TestDecodeVariable(t *testing.T) {
+	value, err := DecodeVariable("isRebooted", []byte(`{"isRebooted": true, "id": "002"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, true, value)
+
+	_, err = DecodeVariable("bad", []byte(`{"isRebooted": true}`))
+	assert.Error(t, err)
+}
+
+func TestDecodeVariables(t *testing.T) {
+	values, err := DecodeVariables([]byte(`{"variables": {"isRebooted": false}, "id": "002"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"isRebooted": false}, values)
+
+	// Numbers are kept
+	values, err = DecodeVariables([]byte(`{"variables": {"counter": 9007199254740993, "temperature": 21.5}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"counter": json.Number("9007199254740993"), "temperature": json.Number("21.5")}, values)
+
+	_, err = DecodeVariables([]byte(`{"id": "002"}`))
+	assert.Error(t, err)
+
+	_, err = DecodeVariables([]byte(`{"variables": "bad"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Errors returned by clients, use errors.Is to check them.
+// They are wrapped with the pin, the variable or the command that produced them.
+var (
+	// ErrNotConnected is returned when a command is sent on board not connected
+	ErrNotConnected = errors.New("Not connected")
+
+	// ErrPinNotConfigured is returned when a pin is used before set its mode
+	ErrPinNotConfigured = errors.New("Pin mode not configured")
+
+	// ErrWrongPinMode is returned when a pin is used with the wrong mode, or when the mode not exist
+	ErrWrongPinMode = errors.New("Wrong pin mode")
+
+	// ErrVariableNotFound is returned when the board not expose the variable
+	ErrVariableNotFound = errors.New("Variable not found")
+
+	// ErrFunctionNotFound is returned when the board not expose the function
+	ErrFunctionNotFound = errors.New("Function not found")
+
+	// ErrTimeout is returned when the board not answer in time
+	ErrTimeout = errors.New("Timeout")
+
+	// ErrProtocol is returned when the board answer is not a valid aREST response
+	ErrProtocol = errors.New("Protocol error")
+)
+
+// PinError is returned when a pin can't be used, it wrap ErrPinNotConfigured or ErrWrongPinMode
+type PinError struct {
+	Pin int
+
+	// Mode is the current pin mode, or the unknown mode asked
+	Mode string
+
+	// Expected is the modes needed by the command
+	Expected []string
+
+	Err error
+}
+
+// Error implement error interface
+func (e *PinError) Error() string {
+	switch {
+	case e.Err == ErrPinNotConfigured:
+		return fmt.Sprintf("You need to set pin mode on pin %d before use it", e.Pin)
+	case len(e.Expected) > 0:
+		return fmt.Sprintf("You need to set pin mode as %v for pin %d before use it, current mode is %s", e.Expected, e.Pin, e.Mode)
+	default:
+		return fmt.Sprintf("Can't found mode %s for pin %d", e.Mode, e.Pin)
+	}
+}
+
+// Unwrap permit to use errors.Is and errors.As
+func (e *PinError) Unwrap() error {
+	return e.Err
+}
+
+// VariableError is returned when a variable can't be read, it wrap ErrVariableNotFound
+type VariableError struct {
+	Name string
+	Err  error
+}
+
+// Error implement error interface
+func (e *VariableError) Error() string {
+	if e.Err == ErrVariableNotFound {
+		return fmt.Sprintf("Variable %s not found", e.Name)
+	}
+	return fmt.Sprintf("Variable %s: %s", e.Name, e.Err.Error())
+}
+
+// Unwrap permit to use errors.Is and errors.As
+func (e *VariableError) Unwrap() error {
+	return e.Err
+}
+
+// FunctionError is returned when a function can't be called, it wrap ErrFunctionNotFound
+type FunctionError struct {
+	Name string
+	Err  error
+}
+
+// Error implement error interface
+func (e *FunctionError) Error() string {
+	if e.Err == ErrFunctionNotFound {
+		return fmt.Sprintf("Function %s not found", e.Name)
+	}
+	return fmt.Sprintf("Function %s: %s", e.Name, e.Err.Error())
+}
+
+// Unwrap permit to use errors.Is and errors.As
+func (e *FunctionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	transport := newMockTransport()
+	p := NewProtocol(transport, false)
+	ctx := context.Background()
+	pinErr := &PinError{}
+
+	// Pin not configured
+	err := p.DigitalWrite(ctx, 3, LevelHigh)
+	assert.True(t, errors.Is(err, ErrPinNotConfigured))
+	assert.True(t, errors.As(err, &pinErr))
+	assert.Equal(t, 3, pinErr.Pin)
+	assert.Equal(t, "You need to set pin mode on pin 3 before use it", err.Error())
+
+	// Wrong pin mode
+	assert.NoError(t, p.SetPinMode(ctx, 3, ModeOutput))
+	_, err = p.DigitalRead(ctx, 3)
+	assert.True(t, errors.Is(err, ErrWrongPinMode))
+	assert.True(t, errors.As(err, &pinErr))
+	assert.Equal(t, ModeOutput, pinErr.Mode)
+	assert.Equal(t, []string{ModeInput, ModeInputPullup}, pinErr.Expected)
+
+	// Unknown mode
+	err = p.SetPinMode(ctx, 3, "bad")
+	assert.True(t, errors.Is(err, ErrWrongPinMode))
+	assert.Equal(t, "Can't found mode bad for pin 3", err.Error())
+
+	// Variable not found
+	transport.responses["/temperature"] = `{"id": "002"}`
+	_, err = p.ReadValue(ctx, "temperature")
+	varErr := &VariableError{}
+	assert.True(t, errors.Is(err, ErrVariableNotFound))
+	assert.True(t, errors.As(err, &varErr))
+	assert.Equal(t, "temperature", varErr.Name)
+
+	// Function not found
+	transport.responses["/test?params=on"] = `{"id": "002"}`
+	_, err = p.CallFunction(ctx, "test", "on")
+	funcErr := &FunctionError{}
+	assert.True(t, errors.Is(err, ErrFunctionNotFound))
+	assert.True(t, errors.As(err, &funcErr))
+	assert.Equal(t, "test", funcErr.Name)
+
+	// Bad response
+	transport.responses["/test?params=on"] = `{"return_value": "ok"}`
+	_, err = p.CallFunction(ctx, "test", "on")
+	assert.True(t, errors.Is(err, ErrProtocol))
+	assert.True(t, errors.As(err, &funcErr))
+	transport.responses["/digital/4"] = "garbage"
+	assert.NoError(t, p.SetPinMode(ctx, 4, ModeInput))
+	_, err = p.DigitalRead(ctx, 4)
+	assert.True(t, errors.Is(err, ErrProtocol))
+}
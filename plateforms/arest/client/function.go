@@ -0,0 +1,45 @@
+package client
+
+// CallFunctionResult represent the aREST response when call function
+type CallFunctionResult struct {
+	ReturnValue int
+	Message     string
+	ID          string
+	Name        string
+	Hardware    string
+	Connected   bool
+}
+
+// DecodeCallFunctionResult permit to decode aREST response when call function
+func DecodeCallFunctionResult(name string, body []byte) (res *CallFunctionResult, err error) {
+	r, err := decodeResponse("/"+name, body)
+	if err != nil {
+		return nil, &FunctionError{Name: name, Err: err}
+	}
+
+	if !r.has("return_value") {
+		return nil, &FunctionError{Name: name, Err: ErrFunctionNotFound}
+	}
+
+	res = &CallFunctionResult{}
+	if res.ReturnValue, err = r.int("return_value"); err != nil {
+		return nil, &FunctionError{Name: name, Err: err}
+	}
+	if res.Message, err = r.string("message"); err != nil {
+		return nil, &FunctionError{Name: name, Err: err}
+	}
+	if res.ID, err = r.id(); err != nil {
+		return nil, &FunctionError{Name: name, Err: err}
+	}
+	if res.Name, err = r.string("name"); err != nil {
+		return nil, &FunctionError{Name: name, Err: err}
+	}
+	if res.Hardware, err = r.string("hardware"); err != nil {
+		return nil, &FunctionError{Name: name, Err: err}
+	}
+	if res.Connected, err = r.bool("connected"); err != nil {
+		return nil, &FunctionError{Name: name, Err: err}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCallFunctionResult(t *testing.T) {
+
+	// Full response
+	res, err := DecodeCallFunctionResult("test", []byte(`{"return_value": 1, "message": "Pump started", "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &CallFunctionResult{
+		ReturnValue: 1,
+		Message:     "Pump started",
+		ID:          "002",
+		Name:        "TFP",
+		Hardware:    "arduino",
+		Connected:   true,
+	}, res)
+
+	// Function not found
+	_, err = DecodeCallFunctionResult("test", []byte(`{"message": "unknown"}`))
+	assert.Error(t, err)
+
+	// Bad return value
+	_, err = DecodeCallFunctionResult("test", []byte(`{"return_value": "1"}`))
+	assert.Error(t, err)
+	_, err = DecodeCallFunctionResult("test", []byte(`{"return_value": 1.5}`))
+	assert.Error(t, err)
+
+	// Bad body
+	_, err = DecodeCallFunctionResult("test", []byte(`{"return_value"`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BoardInfo represent the board identity returned by aREST
+type BoardInfo struct {
+	ID        string
+	Name      string
+	Hardware  string
+	Connected bool
+
+	// Variables is the list of user variables exposed by the board.
+	// It's only filled when the board return them with its identity.
+	Variables []string
+}
+
+// DecodeBoardInfo permit to decode board identity from aREST response of cmd, like IDCommand or RootCommand
+func DecodeBoardInfo(cmd *Command, body []byte) (info *BoardInfo, err error) {
+	r, err := decodeResponse(cmd.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	info = &BoardInfo{
+		Variables: make([]string, 0),
+	}
+
+	if info.ID, err = r.id(); err != nil {
+		return nil, err
+	}
+	if info.Name, err = r.string("name"); err != nil {
+		return nil, err
+	}
+	if info.Hardware, err = r.string("hardware"); err != nil {
+		return nil, err
+	}
+	if info.Connected, err = r.bool("connected"); err != nil {
+		return nil, err
+	}
+	if r.has("variables") {
+		variables, err := r.object("variables")
+		if err != nil {
+			return nil, err
+		}
+		for name := range variables {
+			info.Variables = append(info.Variables, name)
+		}
+		sort.Strings(info.Variables)
+	}
+
+	return info, nil
+}
+
+// BoardMismatchError is returned when the board identity not match the expected one
+type BoardMismatchError struct {
+	Expected *BoardInfo
+	Actual   *BoardInfo
+}
+
+// Error implement error interface
+func (e *BoardMismatchError) Error() string {
+	return fmt.Sprintf("Board mismatch: expected id=%q name=%q hardware=%q, got id=%q name=%q hardware=%q",
+		e.Expected.ID, e.Expected.Name, e.Expected.Hardware,
+		e.Actual.ID, e.Actual.Name, e.Actual.Hardware,
+	)
+}
+
+// Match check the board identity match the expected one
+// Empty fields on expected identity are not checked
+func (b *BoardInfo) Match(expected *BoardInfo) (err error) {
+	if expected == nil {
+		return nil
+	}
+
+	if (expected.ID != "" && expected.ID != b.ID) ||
+		(expected.Name != "" && expected.Name != b.Name) ||
+		(expected.Hardware != "" && expected.Hardware != b.Hardware) {
+		return &BoardMismatchError{
+			Expected: expected,
+			Actual:   b,
+		}
+	}
+
+	return nil
+}
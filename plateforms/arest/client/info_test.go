@@ -0,0 +1,54 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBoardInfo(t *testing.T) {
+
+	// Identity from /id
+	info, err := DecodeBoardInfo(RootCommand(), []byte(`{"id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &BoardInfo{
+		ID:        "002",
+		Name:      "TFP",
+		Hardware:  "arduino",
+		Connected: true,
+		Variables: []string{},
+	}, info)
+
+	// Identity with variables from /
+	info, err = DecodeBoardInfo(RootCommand(), []byte(`{"variables": {"temperature": 24, "isRebooted": false}, "id": 2, "name": "TFP", "hardware": "esp8266", "connected": true}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "2", info.ID)
+	assert.Equal(t, []string{"isRebooted", "temperature"}, info.Variables)
+
+	// Bad body
+	_, err = DecodeBoardInfo(RootCommand(), []byte("bad"))
+	assert.Error(t, err)
+}
+
+func TestBoardInfoMatch(t *testing.T) {
+	info := &BoardInfo{
+		ID:       "002",
+		Name:     "TFP",
+		Hardware: "arduino",
+	}
+
+	// Without expected
+	assert.NoError(t, info.Match(nil))
+
+	// Partial expected
+	assert.NoError(t, info.Match(&BoardInfo{ID: "002"}))
+	assert.NoError(t, info.Match(&BoardInfo{Name: "TFP", Hardware: "arduino"}))
+
+	// Mismatch
+	err := info.Match(&BoardInfo{ID: "002", Name: "pool"})
+	assert.Error(t, err)
+	mismatchErr, ok := err.(*BoardMismatchError)
+	assert.True(t, ok)
+	assert.Equal(t, "pool", mismatchErr.Expected.Name)
+	assert.Equal(t, "TFP", mismatchErr.Actual.Name)
+}
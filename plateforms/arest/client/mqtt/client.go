@@ -0,0 +1,256 @@
+package mqttClient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultTimeout is the timeout to wait the board response when no timeout is provided
+const DefaultTimeout = 10 * time.Second
+
+// Client implement arest interface over MQTT, with the aREST cloud topic convention:
+// commands are published on <id>_in topic and the board answer on <id>_out topic.
+//
+// aREST responses don't carry request identifier, so only one command is sent at a time
+// and the next message received on <id>_out is its response.
+type Client struct {
+	mqtt      mqtt.Client
+	deviceID  string
+	qos       byte
+	timeout   time.Duration
+	isDebug   bool
+	mutex     sync.Mutex
+	responses chan []byte
+	*client.Protocol
+}
+
+// NewClient permit to initialize new client Object
+// The broker is the MQTT broker URL, like tcp://localhost:1883
+func NewClient(broker string, deviceID string, qos byte, timeout time.Duration, isDebug bool) *Client {
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(fmt.Sprintf("gobot-arest-%s-%d", deviceID, time.Now().UnixNano()))
+
+	return NewClientWithOptions(opts, deviceID, qos, timeout, isDebug)
+}
+
+// NewClientWithOptions permit to initialize new client Object with custom MQTT options, like credentials or TLS.
+// Handlers and reconnection settings are overwritten by the client, see SetReconnectPolicy.
+func NewClientWithOptions(opts *mqtt.ClientOptions, deviceID string, qos byte, timeout time.Duration, isDebug bool) *Client {
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	c := &Client{
+		deviceID:  deviceID,
+		qos:       qos,
+		timeout:   timeout,
+		isDebug:   isDebug,
+		mutex:     sync.Mutex{},
+		responses: make(chan []byte, 1),
+	}
+	c.Protocol = client.NewProtocol(c, isDebug)
+
+	// Reconnection is done with the reconnect policy, to restore pins and publish events like other clients
+	opts.
+		SetConnectTimeout(timeout).
+		SetAutoReconnect(false).
+		SetOrderMatters(false).
+		SetConnectionLostHandler(c.onConnectionLost)
+	c.mqtt = mqtt.NewClient(opts)
+
+	return c
+}
+
+// Client permit to get curent MQTT client
+func (c *Client) Client() mqtt.Client {
+	return c.mqtt
+}
+
+// DeviceID return the aREST device ID used on topics
+func (c *Client) DeviceID() string {
+	return c.deviceID
+}
+
+// InTopic return the topic where commands are published
+func (c *Client) InTopic() string {
+	return fmt.Sprintf("%s_in", c.deviceID)
+}
+
+// OutTopic return the topic where the board publish responses
+func (c *Client) OutTopic() string {
+	return fmt.Sprintf("%s_out", c.deviceID)
+}
+
+// Send permit to send command to the board throught MQTT
+// It implement client.Transport interface
+func (c *Client) Send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	if !c.IsConnected() {
+		return nil, client.ErrNotConnected
+	}
+
+	return c.send(ctx, cmd)
+}
+
+// send publish the command and wait the response
+func (c *Client) send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Drop late response of previous command
+	select {
+	case <-c.responses:
+	default:
+	}
+
+	token := c.mqtt.Publish(c.InTopic(), c.qos, false, cmd.String())
+	if !token.WaitTimeout(c.timeout) {
+		return nil, errors.Wrapf(client.ErrTimeout, "Can't publish %s on %s", cmd, c.InTopic())
+	}
+	if err = token.Error(); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case body = <-c.responses:
+		return body, nil
+	case <-timer.C:
+		c.Publish("timeout", true)
+		return nil, errors.Wrapf(client.ErrTimeout, "No response of %s on %s", cmd, c.OutTopic())
+	}
+}
+
+// onMessage receive the board responses
+func (c *Client) onMessage(_ mqtt.Client, msg mqtt.Message) {
+	if c.isDebug {
+		log.Debugf("Message on %s: %s", msg.Topic(), string(msg.Payload()))
+	}
+
+	select {
+	case c.responses <- msg.Payload():
+	default:
+		if c.isDebug {
+			log.Debugf("Drop unexpected message on %s", msg.Topic())
+		}
+	}
+}
+
+// subscribe permit to subscribe on out topic
+func (c *Client) subscribe() (err error) {
+	token := c.mqtt.Subscribe(c.OutTopic(), c.qos, c.onMessage)
+	if !token.WaitTimeout(c.timeout) {
+		return errors.Wrapf(client.ErrTimeout, "Can't subscribe on %s", c.OutTopic())
+	}
+
+	return token.Error()
+}
+
+// onConnectionLost is called by MQTT client when connection is lost
+// It try to reconnect with the reconnect policy
+func (c *Client) onConnectionLost(_ mqtt.Client, err error) {
+	if c.isDebug {
+		log.Debugf("MQTT connection lost: %s", err.Error())
+	}
+	c.SetState(client.StateDisconnected, err.Error())
+	c.Publish("disconnected", true)
+	c.StartReconnect(c.reconnect)
+}
+
+// Connect start connection to the broker and check the board answer
+func (c *Client) Connect(ctx context.Context) (err error) {
+
+	if c.IsConnected() {
+		return
+	}
+
+	c.SetConnecting()
+	defer func() {
+		if err != nil {
+			c.SetConnectFailed(err)
+		}
+	}()
+
+	// Broker can be already connected if the board not answer on previous try
+	if !c.mqtt.IsConnected() {
+		token := c.mqtt.Connect()
+		if !token.WaitTimeout(c.timeout) {
+			return errors.Wrap(client.ErrTimeout, "Can't connect on MQTT broker")
+		}
+		if err = token.Error(); err != nil {
+			return err
+		}
+	}
+
+	if err = c.subscribe(); err != nil {
+		return err
+	}
+
+	// Try connexion
+	// The root url is used because of it return identity and variables
+	body, err := c.send(ctx, client.RootCommand())
+	if err != nil {
+		return err
+	}
+	if _, err = c.HandleInfo(client.RootCommand(), body); err != nil {
+		return err
+	}
+
+	c.Publish("connected", true)
+	c.SetState(client.StateConnected, "connected")
+
+	return nil
+}
+
+// Disconnect close connecion to the broker
+// It stop the reconnect routine if any
+func (c *Client) Disconnect(ctx context.Context) (err error) {
+	c.StopReconnect()
+	c.SetState(client.StateDisconnected, "disconnect")
+
+	if c.mqtt.IsConnected() {
+		c.mqtt.Disconnect(250)
+	}
+
+	c.Publish("disconnected", true)
+
+	return nil
+}
+
+// Reconnect close and start connection to the board
+func (c *Client) Reconnect(ctx context.Context) (err error) {
+	err = c.Disconnect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.reconnect(ctx)
+}
+
+// reconnect start connection to the broker and restore pins
+func (c *Client) reconnect(ctx context.Context) (err error) {
+	err = c.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Set pin mode and output
+	if err = c.RestorePins(ctx); err != nil {
+		return err
+	}
+
+	c.Publish("reconnected", true)
+
+	return nil
+}
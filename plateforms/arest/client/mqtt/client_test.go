@@ -0,0 +1,172 @@
+package mqttClient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+)
+
+type ArestTestSuite struct {
+	suite.Suite
+	broker *mockBroker
+	board  *mockBoard
+	client *Client
+}
+
+func (s *ArestTestSuite) SetupSuite() {
+	// Init logger
+	logrus.SetFormatter(new(prefixed.TextFormatter))
+	logrus.SetLevel(logrus.DebugLevel)
+}
+
+func (s *ArestTestSuite) SetupTest() {
+	s.broker = newMockBroker()
+	s.board = newMockBoard(s.broker.URL(), "greenhouse")
+	s.client = NewClient(s.broker.URL(), "greenhouse", 1, 2*time.Second, true)
+	s.client.SetReconnectPolicy(&client.ReconnectPolicy{InitialInterval: 50 * time.Millisecond, MaxAttempts: 20})
+}
+
+func (s *ArestTestSuite) TearDownTest() {
+	_ = s.client.Disconnect(context.Background())
+	s.board.Close()
+	s.broker.Close()
+}
+
+func TestArestTestSuite(t *testing.T) {
+	suite.Run(t, new(ArestTestSuite))
+}
+
+func (s *ArestTestSuite) TestConnect() {
+	err := s.client.Connect(context.Background())
+	assert.NoError(s.T(), err)
+
+	info, err := s.client.Info(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "esp32", info.Hardware)
+	assert.Equal(s.T(), "greenhouse_in", s.client.InTopic())
+	assert.Equal(s.T(), "greenhouse_out", s.client.OutTopic())
+
+	// Board not online
+	c := NewClient(s.broker.URL(), "unknown", 1, 500*time.Millisecond, false)
+	assert.Error(s.T(), c.Connect(context.Background()))
+	_ = c.Disconnect(context.Background())
+
+	// Broker not reachable
+	c = NewClient("tcp://127.0.0.1:1", "greenhouse", 1, 500*time.Millisecond, false)
+	assert.Error(s.T(), c.Connect(context.Background()))
+}
+
+func (s *ArestTestSuite) TestDigital() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 3, client.ModeOutput))
+	assert.NoError(s.T(), s.client.DigitalWrite(context.Background(), 3, client.LevelHigh))
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 4, client.ModeInput))
+	level, err := s.client.DigitalRead(context.Background(), 4)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), client.LevelHigh, level)
+
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/digital/3/1", "/mode/4/i", "/digital/4"}, s.board.Commands())
+}
+
+func (s *ArestTestSuite) TestCallFunction() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	result, err := s.client.CallFunctionWithResult(context.Background(), "test", "a b&c")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Function test called", result.Message)
+	assert.Equal(s.T(), "/test?params=a+b%26c", s.board.Commands()[1])
+}
+
+func (s *ArestTestSuite) TestTimeout() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	isTimeout := make(chan bool, 1)
+	if err := s.client.On("timeout", func(data interface{}) {
+		isTimeout <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	_, err := s.client.ReadValue(context.Background(), "noanswer")
+	assert.ErrorIs(s.T(), err, client.ErrTimeout)
+
+	select {
+	case <-isTimeout:
+	case <-time.After(time.Second):
+		s.T().Error("timeout event not published")
+	}
+
+	// Next command is not disturbed
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 4, client.ModeInput))
+	level, err := s.client.DigitalRead(context.Background(), 4)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), client.LevelHigh, level)
+}
+
+func (s *ArestTestSuite) TestReconnect() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.DigitalWrite(context.Background(), 3, client.LevelHigh); err != nil {
+		s.T().Fatal(err)
+	}
+
+	err := s.client.Reconnect(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/digital/3/1", "/", "/mode/3/o", "/digital/3/1"}, s.board.Commands())
+}
+
+func (s *ArestTestSuite) TestConnectionLost() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+
+	isDisconnected := make(chan bool, 1)
+	isReconnected := make(chan bool, 1)
+	if err := s.client.On("disconnected", func(data interface{}) {
+		isDisconnected <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.On("reconnected", func(data interface{}) {
+		isReconnected <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	s.broker.Restart()
+
+	select {
+	case <-isDisconnected:
+	case <-time.After(2 * time.Second):
+		s.T().Fatal("disconnected event not published")
+	}
+	select {
+	case <-isReconnected:
+	case <-time.After(3 * time.Second):
+		s.T().Fatal("reconnected event not published")
+	}
+
+	// Pins are restored
+	assert.Contains(s.T(), s.board.Commands()[2:], "/mode/3/o")
+}
@@ -0,0 +1,132 @@
+package mqttClient
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	broker "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+)
+
+// mockBroker is an in-process MQTT broker
+type mockBroker struct {
+	server  *broker.Server
+	address string
+}
+
+func newMockBroker() *mockBroker {
+	// Get free port
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	address := l.Addr().String()
+	_ = l.Close()
+
+	m := &mockBroker{
+		address: address,
+	}
+	m.start()
+
+	return m
+}
+
+func (m *mockBroker) start() {
+	m.server = broker.New(nil)
+	if err := m.server.AddHook(new(auth.AllowHook), nil); err != nil {
+		panic(err)
+	}
+	if err := m.server.AddListener(listeners.NewTCP("t1", m.address, nil)); err != nil {
+		panic(err)
+	}
+	go func() {
+		if err := m.server.Serve(); err != nil {
+			panic(err)
+		}
+	}()
+}
+
+// Restart close all connections and start new broker on same address
+func (m *mockBroker) Restart() {
+	m.Close()
+	m.start()
+}
+
+func (m *mockBroker) URL() string {
+	return fmt.Sprintf("tcp://%s", m.address)
+}
+
+func (m *mockBroker) Close() {
+	_ = m.server.Close()
+}
+
+// mockBoard is a fake aREST board connected on broker
+type mockBoard struct {
+	client   mqtt.Client
+	deviceID string
+	commands []string
+	mutex    sync.Mutex
+}
+
+func newMockBoard(brokerURL string, deviceID string) *mockBoard {
+	m := &mockBoard{
+		deviceID: deviceID,
+		commands: make([]string, 0),
+	}
+	// Subscribe again after broker restart
+	opts := mqtt.NewClientOptions().
+		AddBroker(brokerURL).
+		SetClientID(deviceID).
+		SetMaxReconnectInterval(50 * time.Millisecond).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			c.Subscribe(deviceID+"_in", 1, m.handle)
+		})
+	m.client = mqtt.NewClient(opts)
+	if token := m.client.Connect(); token.WaitTimeout(5*time.Second) && token.Error() != nil {
+		panic(token.Error())
+	}
+
+	return m
+}
+
+func (m *mockBoard) Close() {
+	m.client.Disconnect(250)
+}
+
+func (m *mockBoard) Commands() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string{}, m.commands...)
+}
+
+func (m *mockBoard) handle(c mqtt.Client, msg mqtt.Message) {
+	cmd := string(msg.Payload())
+	m.mutex.Lock()
+	m.commands = append(m.commands, cmd)
+	m.mutex.Unlock()
+
+	// Board that not answer
+	if cmd == "/noanswer" {
+		return
+	}
+
+	c.Publish(m.deviceID+"_out", 1, false, m.response(cmd))
+}
+
+func (m *mockBoard) response(cmd string) string {
+	switch {
+	case cmd == "/":
+		return `{"variables": {"isRebooted": false}, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`
+	case strings.HasPrefix(cmd, "/digital/") && strings.Count(cmd, "/") == 2:
+		return `{"return_value": 1, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`
+	case strings.HasPrefix(cmd, "/test?"):
+		return `{"return_value": 0, "message": "Function test called", "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`
+	default:
+		return fmt.Sprintf(`{"message": "%s done", "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`, cmd)
+	}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxParamsLength is the maximal length of encoded function parameters.
+// aREST store the whole command on small fixed buffer on AVR boards, so longer parameters are truncated by the board.
+const MaxParamsLength = 100
+
+// ParamsSeparator is the separator between arguments on multi-arguments function call
+const ParamsSeparator = ","
+
+// ParamsKeyValueSeparator is the separator between key and value on multi-arguments function call
+const ParamsKeyValueSeparator = "="
+
+// EncodeParams permit to escape function parameters to use it safely on `?params=`.
+// It return error if the encoded parameters exceed MaxParamsLength.
+func EncodeParams(params string) (encoded string, err error) {
+	encoded = url.QueryEscape(params)
+	if len(encoded) > MaxParamsLength {
+		return "", errors.Errorf("Parameters is too long: %d characters once encoded, max is %d", len(encoded), MaxParamsLength)
+	}
+
+	return encoded, nil
+}
+
+// Params permit to build parameters for multi-arguments function call.
+// Arguments are joined with ParamsSeparator, like `on,12` or `pump=on,duration=12`
+type Params struct {
+	args []string
+	err  error
+}
+
+// NewParams return new empty Params
+func NewParams() *Params {
+	return &Params{
+		args: make([]string, 0),
+	}
+}
+
+// Add permit to add arguments on list
+func (p *Params) Add(values ...string) *Params {
+	for _, value := range values {
+		if strings.Contains(value, ParamsSeparator) {
+			p.setErr(errors.Errorf("Argument %q can't contain separator %q", value, ParamsSeparator))
+			continue
+		}
+		p.args = append(p.args, value)
+	}
+
+	return p
+}
+
+// Set permit to add key=value argument
+func (p *Params) Set(key string, value string) *Params {
+	if key == "" || strings.Contains(key, ParamsSeparator) || strings.Contains(key, ParamsKeyValueSeparator) {
+		p.setErr(errors.Errorf("Key %q can't be empty or contain separators %q and %q", key, ParamsSeparator, ParamsKeyValueSeparator))
+		return p
+	}
+	if strings.Contains(value, ParamsSeparator) {
+		p.setErr(errors.Errorf("Value %q of key %s can't contain separator %q", value, key, ParamsSeparator))
+		return p
+	}
+	p.args = append(p.args, key+ParamsKeyValueSeparator+value)
+
+	return p
+}
+
+// Build return the raw parameters, not yet encoded
+// It return the first error found when add arguments or if the encoded parameters are too long
+func (p *Params) Build() (params string, err error) {
+	if p.err != nil {
+		return "", p.err
+	}
+
+	params = strings.Join(p.args, ParamsSeparator)
+	if _, err = EncodeParams(params); err != nil {
+		return "", err
+	}
+
+	return params, nil
+}
+
+func (p *Params) setErr(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeParams(t *testing.T) {
+
+	encoded, err := EncodeParams("test")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", encoded)
+
+	// Special chars
+	encoded, err = EncodeParams("a&b c?d\ne")
+	assert.NoError(t, err)
+	assert.Equal(t, "a%26b+c%3Fd%0Ae", encoded)
+
+	// Too long
+	_, err = EncodeParams(strings.Repeat("a", MaxParamsLength+1))
+	assert.Error(t, err)
+}
+
+func TestParams(t *testing.T) {
+
+	// List
+	params, err := NewParams().Add("on", "12").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "on,12", params)
+
+	// Key / value
+	params, err = NewParams().Set("pump", "on").Set("duration", "12").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "pump=on,duration=12", params)
+
+	// Bad argument
+	_, err = NewParams().Add("a,b").Build()
+	assert.Error(t, err)
+	_, err = NewParams().Set("a=b", "c").Build()
+	assert.Error(t, err)
+	_, err = NewParams().Set("a", "b,c").Build()
+	assert.Error(t, err)
+
+	// Too long
+	_, err = NewParams().Add(strings.Repeat("a", MaxParamsLength+1)).Build()
+	assert.Error(t, err)
+}
@@ -3,4 +3,10 @@ package client
 type Pin struct {
 	Mode  string
 	Value int
+
+	// Duty is the last PWM value written on pin
+	Duty int
+
+	// IsPwm is true when the last write on pin is PWM write
+	IsPwm bool
 }
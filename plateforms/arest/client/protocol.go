@@ -0,0 +1,399 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+	"gobot.io/x/gobot"
+)
+
+// EventQueueSize is the number of events waiting to be published without blocking, next ones are dropped
+const EventQueueSize = 64
+
+// Transport is the link with the board.
+// It only send the command and return the raw response body.
+type Transport interface {
+	Send(ctx context.Context, cmd *Command) (body []byte, err error)
+}
+
+// Protocol implement aREST commands on top of any Transport
+// It keep pin settings and board identity, so transports only need to handle connection
+type Protocol struct {
+	transport Transport
+	isDebug   bool
+	mutexPin  sync.Mutex
+
+	// It permit to set the right mode with digital read / write
+	pins atomic.Value
+	// It permit to cache the board identity read when connect
+	info atomic.Value
+	// It permit to check the board identity when connect
+	expected *BoardInfo
+	// It permit to reconnect on board with policy
+	reconnector reconnector
+	// It permit to track the connection state
+	state stateMachine
+	// It permit to publish command and error events without blocking commands
+	events chan *gobot.Event
+	// It permit to run only one publish routine, while events are waiting
+	isPublishing atomic.Bool
+	gobot.Eventer
+}
+
+// NewProtocol permit to initialize new Protocol object
+func NewProtocol(transport Transport, isDebug bool) *Protocol {
+	p := &Protocol{
+		transport: transport,
+		isDebug:   isDebug,
+		mutexPin:  sync.Mutex{},
+		pins:      atomic.Value{},
+		info:      atomic.Value{},
+		Eventer:   gobot.NewEventer(),
+		events:    make(chan *gobot.Event, EventQueueSize),
+	}
+
+	p.pins.Store(make(map[int]*Pin))
+	p.state.threshold = DefaultDegradedThreshold
+
+	p.AddEvent("connected")
+	p.AddEvent("disconnected")
+	p.AddEvent("reconnected")
+	p.AddEvent("timeout")
+	p.AddEvent("board-mismatch")
+	p.AddEvent("give-up")
+	p.AddEvent("state-changed")
+	p.AddEvent("command")
+	p.AddEvent("error")
+
+	return p
+}
+
+// Pins return the current pins
+func (p *Protocol) Pins() map[int]*Pin {
+	return p.pins.Load().(map[int]*Pin)
+}
+
+// AddPin permit to add pin.
+func (p *Protocol) AddPin(name int, pin *Pin) {
+	p.mutexPin.Lock()
+	defer p.mutexPin.Unlock()
+
+	pins := p.Pins()
+	pins[name] = pin
+	p.pins.Store(pins)
+}
+
+// SetExpectedInfo permit to set the expected board identity
+// Connect and Reconnect failed if the board identity mismatch
+func (p *Protocol) SetExpectedInfo(expected *BoardInfo) {
+	p.expected = expected
+}
+
+// ExpectedInfo return the expected board identity
+func (p *Protocol) ExpectedInfo() *BoardInfo {
+	return p.expected
+}
+
+// Info return the board identity
+// It return the identity read when connect, or read it if not yet available
+func (p *Protocol) Info(ctx context.Context) (info *BoardInfo, err error) {
+	if info, ok := p.info.Load().(*BoardInfo); ok && info != nil {
+		return info, nil
+	}
+
+	return p.ReadInfo(ctx, IDCommand())
+}
+
+// ReadInfo permit to read the board identity with IDCommand or RootCommand
+// It cache the identity and check it match the expected one
+func (p *Protocol) ReadInfo(ctx context.Context, cmd *Command) (info *BoardInfo, err error) {
+	body, err := p.send(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err = p.HandleInfo(cmd, body)
+	p.handleResponseResult(err)
+
+	return info, err
+}
+
+// HandleInfo permit to decode the board identity from raw response
+// It cache the identity and check it match the expected one
+func (p *Protocol) HandleInfo(cmd *Command, body []byte) (info *BoardInfo, err error) {
+	info, err = DecodeBoardInfo(cmd, body)
+	if err != nil {
+		return nil, err
+	}
+	p.info.Store(info)
+
+	if err = info.Match(p.expected); err != nil {
+		p.Publish("board-mismatch", err)
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// RestorePins permit to set again pin mode and output after reconnect
+func (p *Protocol) RestorePins(ctx context.Context) (err error) {
+	for pin, state := range p.Pins() {
+		err = p.SetPinMode(ctx, pin, state.Mode)
+		if err != nil {
+			return err
+		}
+
+		if state.Mode == ModeOutput {
+			if state.IsPwm {
+				err = p.AnalogWrite(ctx, pin, state.Duty)
+			} else {
+				err = p.DigitalWrite(ctx, pin, state.Value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetPinMode permit to set pin mode
+func (p *Protocol) SetPinMode(ctx context.Context, pin int, mode string) (err error) {
+	cmd, err := ModeCommand(pin, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err = p.sendAck(ctx, cmd); err != nil {
+		return err
+	}
+
+	if p.Pins()[pin] == nil {
+		p.AddPin(pin, &Pin{})
+	}
+	p.Pins()[pin].Mode = mode
+
+	return nil
+}
+
+// DigitalWrite permit to set level on pin
+func (p *Protocol) DigitalWrite(ctx context.Context, pin int, level int) (err error) {
+	if err = p.checkPinMode(pin, ModeOutput); err != nil {
+		return err
+	}
+
+	cmd, err := DigitalWriteCommand(pin, level)
+	if err != nil {
+		return err
+	}
+
+	if _, err = p.sendAck(ctx, cmd); err != nil {
+		return err
+	}
+
+	p.Pins()[pin].Value = level
+	p.Pins()[pin].IsPwm = false
+
+	return nil
+}
+
+// DigitalRead permit to read level from pin
+func (p *Protocol) DigitalRead(ctx context.Context, pin int) (level int, err error) {
+	if err = p.checkPinMode(pin, ModeInput, ModeInputPullup); err != nil {
+		return level, err
+	}
+
+	cmd := DigitalReadCommand(pin)
+	body, err := p.send(ctx, cmd)
+	if err != nil {
+		return level, err
+	}
+
+	level, err = DecodeReturnValue(cmd, body)
+	p.handleResponseResult(err)
+
+	return level, err
+}
+
+// AnalogWrite permit to write PWM value on pin
+func (p *Protocol) AnalogWrite(ctx context.Context, pin int, value int) (err error) {
+	if err = p.checkPinMode(pin, ModeOutput); err != nil {
+		return err
+	}
+
+	cmd, err := AnalogWriteCommand(pin, value)
+	if err != nil {
+		return err
+	}
+
+	if _, err = p.sendAck(ctx, cmd); err != nil {
+		return err
+	}
+
+	p.Pins()[pin].Duty = value
+	p.Pins()[pin].IsPwm = true
+
+	return nil
+}
+
+// AnalogRead permit to read analog value from pin
+func (p *Protocol) AnalogRead(ctx context.Context, pin int) (value int, err error) {
+	cmd := AnalogReadCommand(pin)
+	body, err := p.send(ctx, cmd)
+	if err != nil {
+		return value, err
+	}
+
+	value, err = DecodeReturnValue(cmd, body)
+	p.handleResponseResult(err)
+
+	return value, err
+}
+
+// ReadValue permit to read user variable
+func (p *Protocol) ReadValue(ctx context.Context, name string) (value interface{}, err error) {
+	body, err := p.send(ctx, VariableCommand(name))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err = DecodeVariable(name, body)
+	p.handleResponseResult(err)
+
+	return value, err
+}
+
+// ReadValues permit to read all user variables
+func (p *Protocol) ReadValues(ctx context.Context) (values map[string]interface{}, err error) {
+	body, err := p.send(ctx, RootCommand())
+	if err != nil {
+		return nil, err
+	}
+
+	values, err = DecodeVariables(body)
+	p.handleResponseResult(err)
+
+	return values, err
+}
+
+// CallFunction permit to call user function
+func (p *Protocol) CallFunction(ctx context.Context, name string, param string) (value int, err error) {
+	res, err := p.CallFunctionWithResult(ctx, name, param)
+	if err != nil {
+		return value, err
+	}
+
+	return res.ReturnValue, nil
+}
+
+// CallFunctionWithResult permit to call user function and get the full response
+func (p *Protocol) CallFunctionWithResult(ctx context.Context, name string, param string) (res *CallFunctionResult, err error) {
+	cmd, err := FunctionCommand(name, param)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.send(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err = DecodeCallFunctionResult(name, body)
+	p.handleResponseResult(err)
+
+	return res, err
+}
+
+// checkPinMode permit to check pin is set with one of the expected modes
+func (p *Protocol) checkPinMode(pin int, modes ...string) (err error) {
+	if p.Pins()[pin] == nil {
+		return &PinError{Pin: pin, Expected: modes, Err: ErrPinNotConfigured}
+	}
+	for _, mode := range modes {
+		if p.Pins()[pin].Mode == mode {
+			return nil
+		}
+	}
+
+	return &PinError{Pin: pin, Mode: p.Pins()[pin].Mode, Expected: modes, Err: ErrWrongPinMode}
+}
+
+// sendAck permit to send write command and decode the board message
+func (p *Protocol) sendAck(ctx context.Context, cmd *Command) (message string, err error) {
+	body, err := p.send(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	message, err = DecodeAck(cmd, body)
+	p.handleResponseResult(err)
+
+	return message, err
+}
+
+// send permit to send command throught the transport
+// It publish command event with the command before send it, and error event with the error if it failed.
+// The caller must call handleResponseResult with the decode result, so the response is counted by degraded state.
+// These events are published without blocking, so event handlers can send commands.
+func (p *Protocol) send(ctx context.Context, cmd *Command) (body []byte, err error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if p.isDebug {
+		log.Debugf("Command: %s %s", cmd.Method, cmd)
+	}
+
+	p.publishAsync("command", cmd)
+	body, err = p.transport.Send(ctx, cmd)
+	if err != nil {
+		p.handleCommandResult(err)
+		p.publishAsync("error", err)
+		return nil, err
+	}
+
+	if p.isDebug {
+		log.Debugf("Resp: %s", string(body))
+	}
+
+	return body, nil
+}
+
+// publishAsync permit to publish event without blocking the caller
+// The event is dropped if the queue is full, like when handlers are slow.
+func (p *Protocol) publishAsync(name string, data interface{}) {
+	select {
+	case p.events <- gobot.NewEvent(name, data):
+	default:
+		if p.isDebug {
+			log.Debugf("Drop %s event, the event queue is full", name)
+		}
+		return
+	}
+
+	if p.isPublishing.CompareAndSwap(false, true) {
+		go p.publishEvents()
+	}
+}
+
+// publishEvents publish the events queued by publishAsync, in order
+// It stop when the queue is empty, so no routine is left when the client is not used.
+func (p *Protocol) publishEvents() {
+	for {
+		select {
+		case evt := <-p.events:
+			p.Publish(evt.Name, evt.Data)
+		default:
+			p.isPublishing.Store(false)
+			// Event queued while the routine was stopping
+			if len(p.events) == 0 || !p.isPublishing.CompareAndSwap(false, true) {
+				return
+			}
+		}
+	}
+}
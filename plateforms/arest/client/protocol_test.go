@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTransport struct {
+	responses map[string]string
+	commands  []string
+}
+
+func newMockTransport() *mockTransport {
+	return &mockTransport{
+		responses: make(map[string]string),
+		commands:  make([]string, 0),
+	}
+}
+
+func (m *mockTransport) Send(ctx context.Context, cmd *Command) (body []byte, err error) {
+	m.commands = append(m.commands, cmd.String())
+	if resp, ok := m.responses[cmd.String()]; ok {
+		return []byte(resp), nil
+	}
+	return []byte(`{"message": "ok"}`), nil
+}
+
+func TestProtocolPins(t *testing.T) {
+	transport := newMockTransport()
+	p := NewProtocol(transport, true)
+	ctx := context.Background()
+
+	// Need pin mode before use it
+	assert.Error(t, p.DigitalWrite(ctx, 3, LevelHigh))
+	_, err := p.DigitalRead(ctx, 3)
+	assert.Error(t, err)
+
+	// Output
+	assert.NoError(t, p.SetPinMode(ctx, 3, ModeOutput))
+	assert.NoError(t, p.DigitalWrite(ctx, 3, LevelHigh))
+	_, err = p.DigitalRead(ctx, 3)
+	assert.Error(t, err)
+	assert.NoError(t, p.AnalogWrite(ctx, 3, 127))
+	assert.Equal(t, &Pin{Mode: ModeOutput, Value: LevelHigh, Duty: 127, IsPwm: true}, p.Pins()[3])
+
+	// Input
+	transport.responses["/digital/4"] = `{"return_value": 1}`
+	assert.NoError(t, p.SetPinMode(ctx, 4, ModeInputPullup))
+	level, err := p.DigitalRead(ctx, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, LevelHigh, level)
+
+	// Restore
+	transport.commands = make([]string, 0)
+	assert.NoError(t, p.RestorePins(ctx))
+	assert.ElementsMatch(t, []string{"/mode/3/o", "/analog/3/127", "/mode/4/I"}, transport.commands)
+
+	// Mode not updated when board answer garbage
+	transport.responses["/mode/3/i"] = "garbage"
+	assert.Error(t, p.SetPinMode(ctx, 3, ModeInput))
+	assert.Equal(t, ModeOutput, p.Pins()[3].Mode)
+}
+
+func TestProtocolInfo(t *testing.T) {
+	transport := newMockTransport()
+	transport.responses["/id"] = `{"id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
+	p := NewProtocol(transport, false)
+	ctx := context.Background()
+
+	info, err := p.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "TFP", info.Name)
+
+	// Cached
+	_, err = p.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(transport.commands))
+
+	// Mismatch
+	p.SetExpectedInfo(&BoardInfo{Name: "pool"})
+	_, err = p.ReadInfo(ctx, IDCommand())
+	assert.IsType(t, &BoardMismatchError{}, err)
+}
+
+func TestProtocolContextDone(t *testing.T) {
+	p := NewProtocol(newMockTransport(), false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := p.AnalogRead(ctx, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestProtocolEvents(t *testing.T) {
+	transport := &mockFailingTransport{}
+	p := NewProtocol(transport, false)
+	ctx := context.Background()
+
+	commands := make(chan *Command, 1)
+	errs := make(chan error, 1)
+	if err := p.On("command", func(data interface{}) {
+		commands <- data.(*Command)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.On("error", func(data interface{}) {
+		errs <- data.(error)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := p.AnalogRead(ctx, 1)
+	assert.NoError(t, err)
+	select {
+	case cmd := <-commands:
+		assert.Equal(t, "/analog/1", cmd.String())
+	case <-time.After(1 * time.Second):
+		t.Fatal("command event not published")
+	}
+
+	errBoard := errors.New("bad gateway")
+	transport.setError(errBoard)
+	_, err = p.AnalogRead(ctx, 2)
+	assert.ErrorIs(t, err, errBoard)
+	select {
+	case err = <-errs:
+		assert.ErrorIs(t, err, errBoard)
+	case <-time.After(1 * time.Second):
+		t.Fatal("error event not published")
+	}
+	// Publish routine stop when all events are published
+	assert.Eventually(t, func() bool { return !p.isPublishing.Load() }, time.Second, 10*time.Millisecond)
+}
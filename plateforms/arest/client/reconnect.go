@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ReconnectPolicy permit to configure how clients try to reconnect on board when connection is lost.
+// The interval between tries grow from InitialInterval to MaxInterval with Multiplier, and is randomized with Jitter.
+// Fields left to 0 are set from DefaultReconnectPolicy, except MaxAttempts and MaxElapsedTime.
+type ReconnectPolicy struct {
+	// InitialInterval is the interval before the first try
+	InitialInterval time.Duration
+
+	// MaxInterval is the max interval between two tries
+	MaxInterval time.Duration
+
+	// Multiplier is the factor applied on interval after each try
+	Multiplier float64
+
+	// Jitter is the randomization factor between 0 and 1, the interval is randomized in [interval * (1 - Jitter), interval * (1 + Jitter)]
+	// Negative value disable the randomization.
+	Jitter float64
+
+	// MaxAttempts is the max number of tries before give up, 0 for unlimited
+	MaxAttempts int
+
+	// MaxElapsedTime is the max time to try before give up, 0 for unlimited
+	MaxElapsedTime time.Duration
+}
+
+// DefaultReconnectPolicy return the policy used when no policy is set.
+// It try for ever, with interval from 1s to 1m.
+func DefaultReconnectPolicy() *ReconnectPolicy {
+	return &ReconnectPolicy{
+		InitialInterval: 1 * time.Second,
+		MaxInterval:     1 * time.Minute,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+}
+
+// Interval return the interval to wait before the try number attempt, starting from 1
+func (p *ReconnectPolicy) Interval(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	interval := float64(p.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && interval > float64(p.MaxInterval) {
+		interval = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		interval = interval * (1 + p.Jitter*(2*rand.Float64()-1))
+	}
+
+	return time.Duration(interval)
+}
+
+// ReconnectError is published with give-up event when the policy stop trying to reconnect
+type ReconnectError struct {
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+// Error implement error interface
+func (e *ReconnectError) Error() string {
+	return errors.Wrapf(e.Err, "Give up reconnect after %d attempts and %s", e.Attempts, e.Elapsed.Round(time.Millisecond)).Error()
+}
+
+// Unwrap permit to use errors.Is and errors.As with the last reconnect error
+func (e *ReconnectError) Unwrap() error {
+	return e.Err
+}
+
+// reconnector run the reconnect routine, only one at a time
+type reconnector struct {
+	mutex  sync.Mutex
+	policy *ReconnectPolicy
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// SetReconnectPolicy permit to set the policy used to reconnect on board
+// Nil restore the default policy. Fields left to 0 are set from the default policy.
+func (p *Protocol) SetReconnectPolicy(policy *ReconnectPolicy) {
+	if policy != nil {
+		policy = policy.withDefaults()
+	}
+
+	p.reconnector.mutex.Lock()
+	defer p.reconnector.mutex.Unlock()
+	p.reconnector.policy = policy
+}
+
+// withDefaults return copy of policy where fields left to 0 are set from the default policy
+// Without it, partial policy like {MaxAttempts: 10} try to reconnect without wait.
+func (p *ReconnectPolicy) withDefaults() *ReconnectPolicy {
+	defaultPolicy := DefaultReconnectPolicy()
+	policy := *p
+	if policy.InitialInterval <= 0 {
+		policy.InitialInterval = defaultPolicy.InitialInterval
+	}
+	if policy.MaxInterval <= 0 {
+		policy.MaxInterval = defaultPolicy.MaxInterval
+		if policy.MaxInterval < policy.InitialInterval {
+			policy.MaxInterval = policy.InitialInterval
+		}
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = defaultPolicy.Multiplier
+	}
+	if policy.Jitter == 0 {
+		policy.Jitter = defaultPolicy.Jitter
+	}
+
+	return &policy
+}
+
+// ReconnectPolicy return the policy used to reconnect on board
+func (p *Protocol) ReconnectPolicy() *ReconnectPolicy {
+	p.reconnector.mutex.Lock()
+	defer p.reconnector.mutex.Unlock()
+	if p.reconnector.policy == nil {
+		return DefaultReconnectPolicy()
+	}
+	return p.reconnector.policy
+}
+
+// StartReconnect permit to run reconnect in background with the reconnect policy, until it succeed or give up.
+// It do nothing if reconnect routine already run.
+// The state is reconnecting until reconnect succeed. When the policy give up, the state is failed
+// and it publish give-up event with ReconnectError.
+func (p *Protocol) StartReconnect(reconnect func(ctx context.Context) error) {
+	policy := p.ReconnectPolicy()
+
+	p.reconnector.mutex.Lock()
+	defer p.reconnector.mutex.Unlock()
+	if p.reconnector.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.reconnector.ctx = ctx
+	p.reconnector.cancel = cancel
+	p.SetState(StateReconnecting, "connection lost")
+
+	go func() {
+		defer func() {
+			p.reconnector.mutex.Lock()
+			defer p.reconnector.mutex.Unlock()
+			if p.reconnector.ctx == ctx {
+				p.reconnector.ctx = nil
+				p.reconnector.cancel = nil
+			}
+			cancel()
+		}()
+
+		start := time.Now()
+		attempts := 0
+		err := errors.New("Max elapsed time reached before first attempt")
+		for policy.MaxAttempts <= 0 || attempts < policy.MaxAttempts {
+			interval := policy.Interval(attempts + 1)
+			if policy.MaxElapsedTime > 0 && time.Since(start)+interval > policy.MaxElapsedTime {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			attempts++
+			if err = reconnect(ctx); err == nil || ctx.Err() != nil {
+				return
+			}
+			log.Errorf("Reconnect attempt %d failed: %s", attempts, err.Error())
+			p.SetState(StateReconnecting, err.Error())
+		}
+
+		reconnectErr := &ReconnectError{
+			Attempts: attempts,
+			Elapsed:  time.Since(start),
+			Err:      err,
+		}
+		p.SetState(StateFailed, reconnectErr.Error())
+		p.Publish("give-up", reconnectErr)
+	}()
+}
+
+// IsReconnecting return true if the reconnect routine run
+func (p *Protocol) IsReconnecting() bool {
+	p.reconnector.mutex.Lock()
+	defer p.reconnector.mutex.Unlock()
+	return p.reconnector.cancel != nil
+}
+
+// StopReconnect permit to stop the reconnect routine
+func (p *Protocol) StopReconnect() {
+	p.reconnector.mutex.Lock()
+	defer p.reconnector.mutex.Unlock()
+	if p.reconnector.cancel != nil {
+		p.reconnector.cancel()
+		p.reconnector.ctx = nil
+		p.reconnector.cancel = nil
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnectPolicyInterval(t *testing.T) {
+	policy := &ReconnectPolicy{
+		InitialInterval: 1 * time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+	}
+	assert.Equal(t, 1*time.Second, policy.Interval(1))
+	assert.Equal(t, 2*time.Second, policy.Interval(2))
+	assert.Equal(t, 4*time.Second, policy.Interval(3))
+	assert.Equal(t, 5*time.Second, policy.Interval(4))
+	assert.Equal(t, 5*time.Second, policy.Interval(100))
+
+	// With jitter
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		interval := policy.Interval(1)
+		assert.GreaterOrEqual(t, interval, 500*time.Millisecond)
+		assert.LessOrEqual(t, interval, 1500*time.Millisecond)
+	}
+
+	// Default policy when not set
+	p := NewProtocol(newMockTransport(), false)
+	assert.Equal(t, DefaultReconnectPolicy(), p.ReconnectPolicy())
+	p.SetReconnectPolicy(policy)
+	assert.Equal(t, policy, p.ReconnectPolicy())
+
+	// Partial policy is completed with default policy
+	p.SetReconnectPolicy(&ReconnectPolicy{MaxAttempts: 10})
+	assert.Equal(t, &ReconnectPolicy{
+		InitialInterval: 1 * time.Second,
+		MaxInterval:     1 * time.Minute,
+		Multiplier:      2,
+		Jitter:          0.2,
+		MaxAttempts:     10,
+	}, p.ReconnectPolicy())
+	p.SetReconnectPolicy(&ReconnectPolicy{MaxElapsedTime: time.Minute, Jitter: -1})
+	assert.Equal(t, 1*time.Second, p.ReconnectPolicy().Interval(1))
+	assert.Equal(t, time.Minute, p.ReconnectPolicy().MaxElapsedTime)
+}
+
+func TestStartReconnectPartialPolicy(t *testing.T) {
+	p := NewProtocol(newMockTransport(), false)
+	p.SetReconnectPolicy(&ReconnectPolicy{MaxAttempts: 10})
+
+	// It wait the default initial interval before first try
+	var attempts int32
+	p.StartReconnect(func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("board unplugged")
+	})
+	defer p.StopReconnect()
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&attempts))
+	assert.True(t, p.IsReconnecting())
+}
+
+func TestStartReconnect(t *testing.T) {
+	p := NewProtocol(newMockTransport(), false)
+	p.SetReconnectPolicy(&ReconnectPolicy{InitialInterval: 10 * time.Millisecond, MaxAttempts: 3})
+
+	// Give up after max attempts
+	errBoard := errors.New("board unplugged")
+	var attempts int32
+	giveUp := make(chan interface{}, 1)
+	if err := p.On("give-up", func(data interface{}) {
+		giveUp <- data
+	}); err != nil {
+		t.Fatal(err)
+	}
+	p.StartReconnect(func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return errBoard
+	})
+	select {
+	case data := <-giveUp:
+		reconnectErr, ok := data.(*ReconnectError)
+		assert.True(t, ok)
+		assert.Equal(t, 3, reconnectErr.Attempts)
+		assert.ErrorIs(t, reconnectErr, errBoard)
+	case <-time.After(1 * time.Second):
+		t.Fatal("give-up event not published")
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Eventually(t, func() bool { return !p.IsReconnecting() }, time.Second, 10*time.Millisecond)
+
+	// Stop when succeed
+	atomic.StoreInt32(&attempts, 0)
+	p.StartReconnect(func(ctx context.Context) error {
+		if atomic.AddInt32(&attempts, 1) < 2 {
+			return errBoard
+		}
+		return nil
+	})
+	assert.Eventually(t, func() bool { return !p.IsReconnecting() }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+
+	// Stop reconnect
+	p.SetReconnectPolicy(&ReconnectPolicy{InitialInterval: 10 * time.Millisecond})
+	p.StartReconnect(func(ctx context.Context) error {
+		return errBoard
+	})
+	assert.True(t, p.IsReconnecting())
+	p.StopReconnect()
+	assert.False(t, p.IsReconnecting())
+}
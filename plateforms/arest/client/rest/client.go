@@ -2,28 +2,28 @@ package restClient
 
 import (
 	"context"
-	"fmt"
+	"net/url"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	"github.com/go-resty/resty/v2"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
-	"gobot.io/x/gobot"
 )
 
 // Client implement arest interface
 type Client struct {
-	resty     *resty.Client
-	isDebug   bool
-	url       string
-	timeout   time.Duration
-	pins      atomic.Value
-	mutexPin  sync.Mutex
-	connected atomic.Value
-	gobot.Eventer
+	resty    *resty.Client
+	isDebug  bool
+	url      string
+	deviceID string
+	timeout  time.Duration
+
+	// It permit to probe the board on interval, see SetHeartbeat
+	heartbeatInterval time.Duration
+	heartbeatCancel   context.CancelFunc
+	heartbeatMutex    sync.Mutex
+	*client.Protocol
 }
 
 // NewClient permit to initialize new client Object
@@ -38,68 +38,115 @@ func NewClient(url string, timeout time.Duration, isDebug bool) *Client {
 	}
 
 	clientArest := &Client{
-		resty:     resty,
-		isDebug:   isDebug,
-		url:       url,
-		timeout:   timeout,
-		Eventer:   gobot.NewEventer(),
-		pins:      atomic.Value{},
-		mutexPin:  sync.Mutex{},
-		connected: atomic.Value{},
+		resty:   resty,
+		isDebug: isDebug,
+		url:     url,
+		timeout: timeout,
 	}
+	clientArest.Protocol = client.NewProtocol(clientArest, isDebug)
 
-	clientArest.AddEvent("connected")
-	clientArest.AddEvent("disconnected")
-	clientArest.AddEvent("reconnected")
-	clientArest.AddEvent("timeout")
-	clientArest.connected.Store(false)
+	return clientArest
+}
+
+// Device permit to initialize new client Object for board behind relay, like aREST cloud.
+// The commands are sent on <relay URL>/<device ID>/..., and the resty client and its connection pool
+// are shared with the relay client, so one relay can front many boards.
+// Each device use its own heartbeat, with the relay heartbeat interval.
+func (c *Client) Device(deviceID string) *Client {
+	clientArest := &Client{
+		resty:    c.resty,
+		isDebug:  c.isDebug,
+		url:      c.url,
+		deviceID: deviceID,
+		timeout:  c.timeout,
 
-	clientArest.pins.Store(make(map[int]*client.Pin))
+		heartbeatInterval: c.Heartbeat(),
+	}
+	clientArest.Protocol = client.NewProtocol(clientArest, c.isDebug)
 
 	return clientArest
 }
 
+// DeviceID return the device ID used as path prefix, empty if board is not behind relay
+func (c *Client) DeviceID() string {
+	return c.deviceID
+}
+
 // Client permit to get curent resty client
 func (c *Client) Client() *resty.Client {
 	return c.resty
 }
 
-// Pins return the current pins
-func (c *Client) Pins() map[int]*client.Pin {
-	return c.pins.Load().(map[int]*client.Pin)
+// Send permit to send command to the board throught HTTP
+// It implement client.Transport interface
+// It return client.ErrNotConnected if board is not connected, like after Disconnect or while reconnect.
+func (c *Client) Send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	if !c.IsConnected() {
+		return nil, client.ErrNotConnected
+	}
+
+	return c.sendHTTP(ctx, cmd)
 }
 
-// AddPin permit to add pin.
-func (c *Client) AddPin(name int, pin *client.Pin) {
-	c.mutexPin.Lock()
-	defer c.mutexPin.Unlock()
+// sendHTTP permit to send command without check the connection state
+// It's used by Connect and heartbeat to read the board identity.
+func (c *Client) sendHTTP(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	req := c.resty.R().
+		SetHeader("Accept", "application/json").
+		SetContext(ctx)
+
+	if cmd.Query != "" {
+		req.SetQueryString(cmd.Query)
+	}
 
-	pins := c.Pins()
-	pins[name] = pin
-	c.pins.Store(pins)
+	path := cmd.Path
+	if c.deviceID != "" {
+		path = "/" + url.PathEscape(c.deviceID) + path
+	}
+
+	resp, err := req.Execute(cmd.Method, path)
+	if err != nil {
+		if isTimeout(err) {
+			return nil, errors.Wrapf(client.ErrTimeout, "No response from %s for %s: %s", c.url, cmd, err.Error())
+		}
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, newStatusError(resp.StatusCode(), cmd.String(), resp.Body())
+	}
+
+	return resp.Body(), nil
 }
 
 // Connect start connection to the board
-// We just try read port 0 value to check http connexion is ready
+// It read the board identity to check http connexion is ready, then start the heartbeat if enabled
+// The root url is used because of it return identity and variables
 func (c *Client) Connect(ctx context.Context) (err error) {
 
-	_, err = c.resty.R().
-		SetHeader("Accept", "application/json").
-		SetContext(ctx).
-		Get("/id")
+	c.SetConnecting()
+	body, err := c.sendHTTP(ctx, client.RootCommand())
+	if err == nil {
+		_, err = c.HandleInfo(client.RootCommand(), body)
+	}
 	if err != nil {
+		c.SetConnectFailed(err)
 		return err
 	}
 
 	c.Publish("connected", true)
-	c.connected.Store(true)
+	c.SetState(client.StateConnected, "connected")
+	c.startHeartbeat()
 
 	return
 }
 
 // Disconnect close connecion to the board
+// It stop the heartbeat and the reconnect routine if any
 func (c *Client) Disconnect(ctx context.Context) (err error) {
-	c.connected.Store(false)
+	c.stopHeartbeat()
+	c.StopReconnect()
+
+	c.SetState(client.StateDisconnected, "disconnect")
 	c.Publish("disconnected", true)
 	return nil
 }
@@ -110,250 +157,23 @@ func (c *Client) Reconnect(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+
+	return c.reconnect(ctx)
+}
+
+// reconnect start connection to the board, then restore pins
+func (c *Client) reconnect(ctx context.Context) (err error) {
 	err = c.Connect(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Set pin mode and output
-	for pin, state := range c.Pins() {
-		err = c.SetPinMode(ctx, pin, state.Mode)
-		if err != nil {
-			return err
-		}
-
-		if state.Mode == client.ModeOutput {
-			err = c.DigitalWrite(ctx, pin, state.Value)
-			if err != nil {
-				return err
-			}
-		}
+	if err = c.RestorePins(ctx); err != nil {
+		return err
 	}
 
 	c.Publish("reconnected", true)
 
 	return nil
 }
-
-// SetPinMode permit to set pin mode
-func (c *Client) SetPinMode(ctx context.Context, pin int, mode string) (err error) {
-
-	if c.Pins()[pin] == nil {
-		c.AddPin(pin, &client.Pin{})
-	}
-
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		if c.isDebug {
-			log.Debugf("Pin: %d, Mode: %s", pin, mode)
-		}
-
-		if mode != client.ModeInput && mode != client.ModeInputPullup && mode != client.ModeOutput {
-			return errors.Errorf("Can't found mode %s", mode)
-		}
-
-		url := fmt.Sprintf("/mode/%d/%s", pin, mode)
-
-		resp, err := c.resty.R().
-			SetHeader("Accept", "application/json").
-			SetContext(ctx).
-			Post(url)
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp.String())
-		}
-
-		c.Pins()[pin].Mode = mode
-
-		return err
-	}
-}
-
-// DigitalWrite permit to set level on pin
-func (c *Client) DigitalWrite(ctx context.Context, pin int, level int) (err error) {
-
-	if c.Pins()[pin] == nil {
-		return errors.Errorf("You need to set pin mode on pin %d before use it", pin)
-	}
-	if c.Pins()[pin].Mode != client.ModeOutput {
-		return errors.Errorf("You need to set pin mode as output for pin %d before write on it", pin)
-	}
-
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		if c.isDebug {
-			log.Debugf("Pin: %d, Level: %d", pin, level)
-		}
-
-		if level != client.LevelHigh && level != client.LevelLow {
-			return errors.Errorf("Can't found level %d", level)
-		}
-
-		url := fmt.Sprintf("/digital/%d/%d", pin, level)
-
-		resp, err := c.resty.R().
-			SetHeader("Accept", "application/json").
-			SetContext(ctx).
-			Post(url)
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp.String())
-		}
-
-		c.Pins()[pin].Value = level
-
-		return err
-	}
-}
-
-// DigitalRead permit to read level from pin
-func (c *Client) DigitalRead(ctx context.Context, pin int) (level int, err error) {
-
-	if c.Pins()[pin] == nil {
-		return 0, errors.Errorf("You need to set pin mode on pin %d before use it", pin)
-	}
-	if c.Pins()[pin].Mode != client.ModeInput && c.Pins()[pin].Mode != client.ModeInputPullup {
-		return 0, errors.Errorf("You need to set pin mode as input or input_pullup for pin %d before read on it", pin)
-	}
-
-	select {
-	case <-ctx.Done():
-		return level, ctx.Err()
-	default:
-
-		if c.isDebug {
-			log.Debugf("Pin: %d", pin)
-		}
-
-		url := fmt.Sprintf("/digital/%d", pin)
-		data := make(map[string]interface{})
-
-		resp, err := c.resty.R().
-			SetHeader("Accept", "application/json").
-			SetContext(ctx).
-			SetResult(&data).
-			Get(url)
-		if err != nil {
-			return level, err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s, %+v", resp.String(), data)
-		}
-
-		return int(data["return_value"].(float64)), nil
-	}
-}
-
-// ReadValue permit to read user variable
-func (c *Client) ReadValue(ctx context.Context, name string) (value interface{}, err error) {
-
-	select {
-	case <-ctx.Done():
-		return value, ctx.Err()
-	default:
-		if c.isDebug {
-			log.Debugf("Value name: %s", name)
-		}
-
-		url := fmt.Sprintf("/%s", name)
-		data := make(map[string]interface{})
-
-		resp, err := c.resty.R().
-			SetHeader("Accept", "application/json").
-			SetContext(ctx).
-			SetResult(&data).
-			Get(url)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Debugf("Resp: %s", resp.String())
-
-		if temp, ok := data[name]; ok {
-			value = temp
-		} else {
-			err = errors.Errorf("Variable %s not found", name)
-		}
-
-		return value, err
-	}
-}
-
-// ReadValues permit to read user variable
-func (c *Client) ReadValues(ctx context.Context) (values map[string]interface{}, err error) {
-	select {
-	case <-ctx.Done():
-		return values, ctx.Err()
-	default:
-
-		data := make(map[string]interface{})
-
-		resp, err := c.resty.R().
-			SetHeader("Accept", "application/json").
-			SetContext(ctx).
-			SetResult(&data).
-			Get("/")
-		if err != nil {
-			return nil, err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp.String())
-		}
-
-		if temp, ok := data["variables"]; ok {
-			values = temp.(map[string]interface{})
-		} else {
-			err = errors.Errorf("No variable found")
-		}
-
-		return values, err
-	}
-}
-
-// CallFunction permit to call user function
-func (c *Client) CallFunction(ctx context.Context, name string, param string) (value int, err error) {
-
-	select {
-	case <-ctx.Done():
-		return value, ctx.Err()
-	default:
-
-		if c.isDebug {
-			log.Debugf("Function: %s, param: %s", name, param)
-		}
-
-		url := fmt.Sprintf("/%s", name)
-
-		data := make(map[string]interface{})
-
-		resp, err := c.resty.R().
-			SetQueryParams(map[string]string{
-				"params": param,
-			}).
-			SetHeader("Accept", "application/json").
-			SetContext(ctx).
-			SetResult(&data).
-			Post(url)
-		if err != nil {
-			return value, err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp.String())
-		}
-
-		if temp, ok := data["return_value"]; ok {
-			value = int(temp.(float64))
-		} else {
-			return value, errors.Errorf("Function %s not found", name)
-		}
-
-		return value, err
-	}
-}
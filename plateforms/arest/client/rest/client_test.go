@@ -2,7 +2,11 @@ package restClient
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	"github.com/jarcoal/httpmock"
@@ -41,32 +45,148 @@ func TestArestTestSuite(t *testing.T) {
 }
 
 func (s *ArestTestSuite) TestConnect() {
-	fixture := `{"message": "Pin D0 set to output", "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
+	fixture := `{"variables": {"temperature": 20, "humidity": 50}, "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
 	responder := httpmock.NewStringResponder(200, fixture)
-	fakeURL := "http://localhost/id"
+	fakeURL := "http://localhost/"
 	httpmock.RegisterResponder("GET", fakeURL, responder)
 
 	err := s.client.Connect(context.Background())
 	assert.NoError(s.T(), err)
-	assert.True(s.T(), s.client.connected.Load().(bool))
+	assert.True(s.T(), s.client.IsConnected())
+
+	info, err := s.client.Info(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "002", info.ID)
+	assert.Equal(s.T(), "TFP", info.Name)
+	assert.Equal(s.T(), "arduino", info.Hardware)
+	assert.True(s.T(), info.Connected)
+	assert.ElementsMatch(s.T(), []string{"temperature", "humidity"}, info.Variables)
+
+	// Bad identity
+	s.client = MockRestClient()
+	httpmock.RegisterResponder("GET", fakeURL, httpmock.NewStringResponder(200, "<html></html>"))
+	err = s.client.Connect(context.Background())
+	assert.Error(s.T(), err)
+}
+
+func (s *ArestTestSuite) TestConnectExpectedInfo() {
+	fixture := `{"id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
+	responder := httpmock.NewStringResponder(200, fixture)
+	httpmock.RegisterResponder("GET", "http://localhost/", responder)
+
+	// Board match
+	s.client.SetExpectedInfo(&client.BoardInfo{ID: "002", Hardware: "arduino"})
+	err := s.client.Connect(context.Background())
+	assert.NoError(s.T(), err)
+
+	// Board mismatch
+	var isPublished atomic.Bool
+	if err := s.client.On("board-mismatch", func(data interface{}) {
+		isPublished.Store(true)
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+	s.client.SetExpectedInfo(&client.BoardInfo{ID: "003"})
+	err = s.client.Reconnect(context.Background())
+	assert.Error(s.T(), err)
+	assert.IsType(s.T(), &client.BoardMismatchError{}, err)
+	assert.False(s.T(), s.client.IsConnected())
+	assert.Eventually(s.T(), func() bool { return isPublished.Load() }, 1*time.Second, 10*time.Millisecond)
 }
 
 func (s *ArestTestSuite) TestDisconnect() {
 
 	err := s.client.Disconnect(context.Background())
 	assert.NoError(s.T(), err)
-	assert.False(s.T(), s.client.connected.Load().(bool))
+	assert.False(s.T(), s.client.IsConnected())
+
+	// Commands are not sent
+	httpmock.RegisterResponder("GET", "http://localhost/analog/1", httpmock.NewStringResponder(200, `{"return_value": 1}`))
+	_, err = s.client.AnalogRead(context.Background(), 1)
+	assert.ErrorIs(s.T(), err, client.ErrNotConnected)
+	assert.Equal(s.T(), 0, httpmock.GetTotalCallCount())
 }
 
 func (s *ArestTestSuite) TestReconnect() {
 	fixture := `{"message": "Pin D0 set to output", "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
 	responder := httpmock.NewStringResponder(200, fixture)
-	fakeURL := "http://localhost/id"
+	fakeURL := "http://localhost/"
 	httpmock.RegisterResponder("GET", fakeURL, responder)
 
 	err := s.client.Reconnect(context.Background())
 	assert.NoError(s.T(), err)
-	assert.True(s.T(), s.client.connected.Load().(bool))
+	assert.True(s.T(), s.client.IsConnected())
+}
+
+func (s *ArestTestSuite) TestDevice() {
+	httpmock.RegisterResponder("GET", "http://localhost/abc123/", httpmock.NewStringResponder(200, `{"id": "abc123", "name": "greenhouse", "hardware": "esp8266", "connected": true}`))
+	httpmock.RegisterResponder("GET", "http://localhost/def456/", httpmock.NewStringResponder(200, `{"id": "def456", "name": "pool", "hardware": "esp8266", "connected": true}`))
+	httpmock.RegisterResponder("POST", "http://localhost/abc123/mode/3/o", httpmock.NewStringResponder(200, `{"message": "Pin D3 set to output", "id": "abc123", "name": "greenhouse", "hardware": "esp8266", "connected": true}`))
+
+	greenhouse := s.client.Device("abc123")
+	pool := s.client.Device("def456")
+	assert.Equal(s.T(), "abc123", greenhouse.DeviceID())
+
+	// Same HTTP client for all devices
+	assert.Same(s.T(), s.client.Client(), greenhouse.Client())
+	assert.Same(s.T(), s.client.Client(), pool.Client())
+
+	assert.NoError(s.T(), greenhouse.Connect(context.Background()))
+	assert.NoError(s.T(), pool.Connect(context.Background()))
+	info, err := pool.Info(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "pool", info.Name)
+
+	// Pins are not shared
+	assert.NoError(s.T(), greenhouse.SetPinMode(context.Background(), 3, client.ModeOutput))
+	assert.Len(s.T(), greenhouse.Pins(), 1)
+	assert.Len(s.T(), pool.Pins(), 0)
+}
+
+func (s *ArestTestSuite) TestStatusError() {
+	httpmock.RegisterResponder("POST", "http://localhost/mode/3/o", httpmock.NewStringResponder(500, `{"message": "Pin 3 is reserved", "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`))
+	httpmock.RegisterResponder("POST", "http://localhost/mode/4/o", httpmock.NewStringResponder(200, `{"message": "Pin D4 set to output", "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`))
+	httpmock.RegisterResponder("POST", "http://localhost/digital/4/1", httpmock.NewStringResponder(502, "<html><body>Bad Gateway</body></html>"))
+	httpmock.RegisterResponder("GET", "http://localhost/", httpmock.NewStringResponder(401, ""))
+
+	// aREST error payload
+	err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput)
+	statusErr := &StatusError{}
+	assert.ErrorAs(s.T(), err, &statusErr)
+	assert.Equal(s.T(), 500, statusErr.StatusCode)
+	assert.Equal(s.T(), "/mode/3/o", statusErr.Command)
+	assert.Equal(s.T(), "Pin 3 is reserved", statusErr.Message)
+	assert.ErrorIs(s.T(), err, client.ErrProtocol)
+	assert.Nil(s.T(), s.client.Pins()[3])
+
+	// Proxy error page
+	if err = s.client.SetPinMode(context.Background(), 4, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	err = s.client.DigitalWrite(context.Background(), 4, client.LevelHigh)
+	assert.ErrorAs(s.T(), err, &statusErr)
+	assert.Equal(s.T(), 502, statusErr.StatusCode)
+	assert.Empty(s.T(), statusErr.Message)
+	assert.Contains(s.T(), statusErr.Error(), "Bad Gateway")
+	assert.Equal(s.T(), client.LevelLow, s.client.Pins()[4].Value)
+
+	// Connect refused
+	err = s.client.Connect(context.Background())
+	assert.ErrorAs(s.T(), err, &statusErr)
+	assert.Equal(s.T(), 401, statusErr.StatusCode)
+	assert.False(s.T(), s.client.IsConnected())
+}
+
+func (s *ArestTestSuite) TestTimeout() {
+	httpmock.RegisterResponder("GET", "http://localhost/analog/1", httpmock.NewErrorResponder(context.DeadlineExceeded))
+	httpmock.RegisterResponder("GET", "http://localhost/analog/2", httpmock.NewErrorResponder(errors.New("connection refused")))
+
+	_, err := s.client.AnalogRead(context.Background(), 1)
+	assert.ErrorIs(s.T(), err, client.ErrTimeout)
+
+	_, err = s.client.AnalogRead(context.Background(), 2)
+	assert.Error(s.T(), err)
+	assert.NotErrorIs(s.T(), err, client.ErrTimeout)
 }
 
 func (s *ArestTestSuite) TestSetMode() {
@@ -141,6 +261,69 @@ func (s *ArestTestSuite) TestDigitalRead() {
 	assert.Equal(s.T(), client.LevelHigh, level)
 }
 
+func (s *ArestTestSuite) TestAnalogWrite() {
+
+	fixture := `{"message": "Pin D3 set to 127", "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
+	responder := httpmock.NewStringResponder(200, fixture)
+	httpmock.RegisterResponder("POST", "http://localhost/analog/3/127", responder)
+	httpmock.RegisterResponder("POST", "http://localhost/mode/3/i", responder)
+	httpmock.RegisterResponder("POST", "http://localhost/mode/3/o", responder)
+
+	// Return error if pin is not yet setted
+	err := s.client.AnalogWrite(context.Background(), 3, 127)
+	assert.Error(s.T(), err)
+
+	// Return error if pin is not output mode
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeInput); err != nil {
+		s.T().Fatal(err)
+	}
+	err = s.client.AnalogWrite(context.Background(), 3, 127)
+	assert.Error(s.T(), err)
+
+	// Normal use case
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	err = s.client.AnalogWrite(context.Background(), 3, 127)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 127, s.client.Pins()[3].Duty)
+	assert.True(s.T(), s.client.Pins()[3].IsPwm)
+
+	// Out of range
+	err = s.client.AnalogWrite(context.Background(), 3, 256)
+	assert.Error(s.T(), err)
+
+	// Replay PWM on reconnect
+	httpmock.RegisterResponder("GET", "http://localhost/", responder)
+	err = s.client.Reconnect(context.Background())
+	assert.NoError(s.T(), err)
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(s.T(), 2, info["POST http://localhost/analog/3/127"])
+}
+
+func (s *ArestTestSuite) TestAnalogRead() {
+
+	fixture := map[string]interface{}{
+		"return_value": 512,
+	}
+	responder := httpmock.NewJsonResponderOrPanic(200, fixture)
+	fakeURL := "http://localhost/analog/0"
+	httpmock.RegisterResponder("GET", fakeURL, responder)
+
+	value, err := s.client.AnalogRead(context.Background(), 0)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 512, value)
+
+	// Bad return value
+	fixture = map[string]interface{}{
+		"return_value": "bad",
+	}
+	responder = httpmock.NewJsonResponderOrPanic(200, fixture)
+	httpmock.RegisterResponder("GET", fakeURL, responder)
+	_, err = s.client.AnalogRead(context.Background(), 0)
+	assert.Error(s.T(), err)
+}
+
 func (s *ArestTestSuite) TestReadValue() {
 
 	//fixture := `{"isRebooted": true, "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
@@ -184,6 +367,7 @@ func (s *ArestTestSuite) TestCallFunction() {
 	//fixture := `{"return_value": 1, "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
 	fixture := map[string]interface{}{
 		"return_value": 1,
+		"message":      "Rebooted acknowledged",
 	}
 	responder := httpmock.NewJsonResponderOrPanic(200, fixture)
 	fakeURL := "http://localhost/acknoledgeRebooted?params=test"
@@ -193,7 +377,91 @@ func (s *ArestTestSuite) TestCallFunction() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), 1, resp)
 
+	res, err := s.client.CallFunctionWithResult(context.Background(), "acknoledgeRebooted", "test")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, res.ReturnValue)
+	assert.Equal(s.T(), "Rebooted acknowledged", res.Message)
+
 	// Bad
 	_, err = s.client.CallFunction(context.Background(), "bad", "test")
 	assert.Error(s.T(), err)
 }
+
+func (s *ArestTestSuite) TestCallFunctionEscapeParams() {
+
+	fixture := map[string]interface{}{
+		"return_value": 1,
+	}
+	responder := httpmock.NewJsonResponderOrPanic(200, fixture)
+	httpmock.RegisterResponder("POST", "http://localhost/setMode?params=a%26b+c", responder)
+
+	resp, err := s.client.CallFunction(context.Background(), "setMode", "a&b c")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, resp)
+
+	// Too long
+	_, err = s.client.CallFunction(context.Background(), "setMode", strings.Repeat("a", client.MaxParamsLength+1))
+	assert.Error(s.T(), err)
+}
+
+func (s *ArestTestSuite) TestHeartbeat() {
+	fixture := `{"message": "ok", "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`
+	httpmock.RegisterResponder("GET", "http://localhost/", httpmock.NewStringResponder(200, fixture))
+	httpmock.RegisterResponder("POST", "http://localhost/mode/3/o", httpmock.NewStringResponder(200, fixture))
+	httpmock.RegisterResponder("POST", "http://localhost/digital/3/1", httpmock.NewStringResponder(200, fixture))
+
+	s.client.SetHeartbeat(50 * time.Millisecond)
+	s.client.SetReconnectPolicy(&client.ReconnectPolicy{InitialInterval: 50 * time.Millisecond, MaxAttempts: 20})
+	defer s.client.Disconnect(context.Background())
+
+	isTimeout := make(chan bool, 1)
+	isDisconnected := make(chan bool, 1)
+	isReconnected := make(chan bool, 1)
+	for event, c := range map[string]chan bool{"timeout": isTimeout, "disconnected": isDisconnected, "reconnected": isReconnected} {
+		c := c
+		if err := s.client.On(event, func(data interface{}) {
+			select {
+			case c <- true:
+			default:
+			}
+		}); err != nil {
+			s.T().Fatal(err)
+		}
+	}
+
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.DigitalWrite(context.Background(), 3, client.LevelHigh); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Board goes away
+	httpmock.RegisterResponder("GET", "http://localhost/", httpmock.NewErrorResponder(context.DeadlineExceeded))
+	select {
+	case <-isDisconnected:
+	case <-time.After(2 * time.Second):
+		s.T().Fatal("disconnected event not published")
+	}
+	select {
+	case <-isTimeout:
+	case <-time.After(1 * time.Second):
+		s.T().Fatal("timeout event not published")
+	}
+	assert.False(s.T(), s.client.IsConnected())
+
+	// Board is back, pins are restored
+	httpmock.RegisterResponder("GET", "http://localhost/", httpmock.NewStringResponder(200, fixture))
+	select {
+	case <-isReconnected:
+	case <-time.After(2 * time.Second):
+		s.T().Fatal("reconnected event not published")
+	}
+	assert.Equal(s.T(), client.StateConnected, s.client.State())
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(s.T(), 2, info["POST http://localhost/mode/3/o"])
+	assert.Equal(s.T(), 2, info["POST http://localhost/digital/3/1"])
+}
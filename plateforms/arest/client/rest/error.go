@@ -0,0 +1,65 @@
+package restClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+)
+
+// maxErrorBodyLength is the max body length kept on error, to not log full proxy error page
+const maxErrorBodyLength = 256
+
+// StatusError is returned when the board, or a proxy in front of it, answer with HTTP status other than 2xx
+type StatusError struct {
+	// StatusCode is the HTTP status code, like 404
+	StatusCode int
+
+	// Command is the aREST request line, like /digital/3/1
+	Command string
+
+	// Message is the aREST error message, empty if body is not aREST error payload
+	Message string
+
+	// Body is the raw response body, truncated
+	Body string
+}
+
+// Error implement error interface
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Board return HTTP %d on %s: %s", e.StatusCode, e.Command, e.Message)
+	}
+	return fmt.Sprintf("Board return HTTP %d on %s: %s", e.StatusCode, e.Command, e.Body)
+}
+
+// Unwrap permit to use errors.Is with client.ErrProtocol
+func (e *StatusError) Unwrap() error {
+	return client.ErrProtocol
+}
+
+// newStatusError permit to build StatusError and decode the aREST error payload if any
+// aREST error payload is JSON object with message or error field.
+func newStatusError(statusCode int, command string, body []byte) *StatusError {
+	e := &StatusError{
+		StatusCode: statusCode,
+		Command:    command,
+		Body:       strings.TrimSpace(string(body)),
+	}
+	if len(e.Body) > maxErrorBodyLength {
+		e.Body = e.Body[:maxErrorBodyLength] + "..."
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(body, &data); err == nil {
+		for _, key := range []string{"message", "error"} {
+			if message, ok := data[key].(string); ok && message != "" {
+				e.Message = message
+				break
+			}
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,117 @@
+package restClient
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetHeartbeat permit to probe the board on interval to detect when it goes away.
+// When probe failed, the client publish disconnected event and try to reconnect with the reconnect policy,
+// then restore pin modes and outputs like the serial client. 0 disable the heartbeat.
+// It need to be called before Connect.
+func (c *Client) SetHeartbeat(interval time.Duration) {
+	c.heartbeatMutex.Lock()
+	defer c.heartbeatMutex.Unlock()
+	c.heartbeatInterval = interval
+}
+
+// Heartbeat return the heartbeat interval, 0 if disabled
+func (c *Client) Heartbeat() time.Duration {
+	c.heartbeatMutex.Lock()
+	defer c.heartbeatMutex.Unlock()
+	return c.heartbeatInterval
+}
+
+// startHeartbeat run the heartbeat routine if enabled and not yet running
+func (c *Client) startHeartbeat() {
+	c.heartbeatMutex.Lock()
+	defer c.heartbeatMutex.Unlock()
+	if c.heartbeatInterval <= 0 || c.heartbeatCancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.heartbeatCancel = cancel
+	go c.heartbeat(ctx, c.heartbeatInterval)
+}
+
+// stopHeartbeat stop the heartbeat routine if any
+func (c *Client) stopHeartbeat() {
+	c.heartbeatMutex.Lock()
+	defer c.heartbeatMutex.Unlock()
+	if c.heartbeatCancel != nil {
+		c.heartbeatCancel()
+		c.heartbeatCancel = nil
+	}
+}
+
+// heartbeat probe the board on each interval until context is cancelled
+// It skip probe when connection is already lost, the reconnect routine take the lead.
+func (c *Client) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !c.IsConnected() || c.IsReconnecting() {
+			continue
+		}
+
+		if err := c.probe(ctx, interval); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.handleLost(err)
+		}
+	}
+}
+
+// probe read the board identity
+// It wait at most the client timeout, or the heartbeat interval if no timeout is set.
+func (c *Client) probe(ctx context.Context, interval time.Duration) (err error) {
+	timeout := c.timeout
+	if timeout == 0 {
+		timeout = interval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := c.sendHTTP(ctx, client.RootCommand())
+	if err != nil {
+		return err
+	}
+	_, err = c.HandleInfo(client.RootCommand(), body)
+	return err
+}
+
+// handleLost mark the board as disconnected and start the reconnect routine
+func (c *Client) handleLost(err error) {
+	log.Warnf("Lost connection with board %s: %s", c.url, err.Error())
+
+	if errors.Is(err, client.ErrTimeout) {
+		c.Publish("timeout", err)
+	}
+	c.SetState(client.StateDisconnected, err.Error())
+	c.Publish("disconnected", true)
+
+	c.StartReconnect(c.reconnect)
+}
+
+// isTimeout return true if the HTTP request failed because of timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, client.ErrTimeout) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
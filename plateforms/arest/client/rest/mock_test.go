@@ -3,12 +3,16 @@ package restClient
 import (
 	"time"
 
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	"github.com/jarcoal/httpmock"
 )
 
 func MockRestClient() *Client {
-	client := NewClient("http://localhost", 1*time.Second, true)
-	httpmock.ActivateNonDefault(client.Client().GetClient())
+	c := NewClient("http://localhost", 1*time.Second, true)
+	httpmock.ActivateNonDefault(c.Client().GetClient())
 
-	return client
+	// Commands are only sent when connected
+	c.SetState(client.StateConnected, "connected")
+
+	return c
 }
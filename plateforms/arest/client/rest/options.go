@@ -0,0 +1,87 @@
+package restClient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Options permit to set authentication, TLS and proxy used on every HTTP request, and the heartbeat
+type Options struct {
+	// Username and Password are used for basic authentication when Username is set
+	Username string
+	Password string
+
+	// Headers are added on every request, like API key header
+	Headers http.Header
+
+	// TLSConfig is the TLS config used on HTTPS, see NewTLSConfig
+	TLSConfig *tls.Config
+
+	// Proxy is the HTTP proxy URL, like http://proxy:3128
+	Proxy string
+
+	// Heartbeat is the interval to probe the board, 0 to disable it. See SetHeartbeat
+	Heartbeat time.Duration
+}
+
+// NewTLSConfig permit to build TLS config from files.
+// The caFile is the PEM CA bundle used to check the board certificate, empty to use system CA.
+// The certFile and keyFile are the PEM client certificate and its key, empty to not use client certificate.
+// The insecureSkipVerify disable the board certificate check, use it only on lab.
+func NewTLSConfig(caFile string, certFile string, keyFile string, insecureSkipVerify bool) (config *tls.Config, err error) {
+	config = &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Can't read CA file %s", caFile)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("No certificate found on CA file %s", caFile)
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Can't load client certificate %s", certFile)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// SetOptions permit to apply authentication, TLS, proxy and heartbeat options on HTTP client
+// It need to be called before Connect.
+func (c *Client) SetOptions(opts *Options) {
+	if opts == nil {
+		return
+	}
+
+	if opts.Username != "" {
+		c.resty.SetBasicAuth(opts.Username, opts.Password)
+	}
+	for key, values := range opts.Headers {
+		for _, value := range values {
+			c.resty.Header.Add(key, value)
+		}
+	}
+	if opts.TLSConfig != nil {
+		c.resty.SetTLSClientConfig(opts.TLSConfig)
+	}
+	if opts.Proxy != "" {
+		c.resty.SetProxy(opts.Proxy)
+	}
+	if opts.Heartbeat > 0 {
+		c.SetHeartbeat(opts.Heartbeat)
+	}
+}
@@ -0,0 +1,58 @@
+package restClient
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "admin" || password != "secret" || r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`))
+	}))
+	defer server.Close()
+
+	// Write server certificate as CA bundle
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig, err := NewTLSConfig(caFile, "", "", false)
+	assert.NoError(t, err)
+
+	c := NewClient(server.URL, 1*time.Second, false)
+	c.SetOptions(&Options{
+		Username:  "admin",
+		Password:  "secret",
+		Headers:   http.Header{"X-Api-Key": []string{"key"}},
+		TLSConfig: tlsConfig,
+	})
+	assert.NoError(t, c.Connect(context.Background()))
+
+	// Unknown CA
+	c = NewClient(server.URL, 1*time.Second, false)
+	assert.Error(t, c.Connect(context.Background()))
+
+	// Bad files
+	_, err = NewTLSConfig(filepath.Join(t.TempDir(), "none.pem"), "", "", false)
+	assert.Error(t, err)
+	_, err = NewTLSConfig("", caFile, caFile, false)
+	assert.Error(t, err)
+	emptyFile := filepath.Join(t.TempDir(), "empty.pem")
+	_ = os.WriteFile(emptyFile, []byte("empty"), 0600)
+	_, err = NewTLSConfig(emptyFile, "", "", false)
+	assert.Error(t, err)
+}
@@ -2,19 +2,16 @@ package serialClient
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/disaster37/gobot-arest/plateforms/arest/client"
-	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 	"go.bug.st/serial"
-	"gobot.io/x/gobot"
 )
 
+// DefaultTimeout is the timeout to wait the board response when no timeout is provided
+const DefaultTimeout = 5 * time.Second
+
 // Client implement arest interface
 type Client struct {
 	serialPort serial.Port
@@ -23,60 +20,68 @@ type Client struct {
 	port       string
 	timeout    time.Duration
 	mutex      sync.Mutex
-	mutexPin   sync.Mutex
 
-	// It permit to exchange error, result and start watchdog between read routine and write
+	// It permit to open the port when connect
+	open PortOpener
+	// It permit to stop read routine when disconnect
+	cancel context.CancelFunc
+
+	// It permit to exchange error, result and activity between read routine and write
 	com *Com
+	// It permit to split the serial stream in responses
+	framing *Framing
 
-	// It permit to know if current connexion is connected
-	connected atomic.Value
-	// It permit to set the right mode with digital read / write
-	pins atomic.Value
-	gobot.Eventer
+	*client.Protocol
 }
 
+// PortOpener permit to open the port used by the client.
+// It permit to use the serial line protocol over other link than serial, like TCP.
+type PortOpener func() (serial.Port, error)
+
 // NewClient permit to initialize new client Object
+// DefaultTimeout is used when timeout is 0.
 func NewClient(port string, serialMode *serial.Mode, timeout time.Duration, isDebug bool) *Client {
+	clientArest := newClient(port, timeout, isDebug)
+	clientArest.serialMode = serialMode
+	clientArest.open = func() (serial.Port, error) {
+		return serial.Open(port, serialMode)
+	}
+
+	return clientArest
+}
+
+// NewClientWithOpener permit to initialize new client Object that use the serial line protocol on port returned by opener.
+// The name is only used to identify the port.
+func NewClientWithOpener(name string, opener PortOpener, timeout time.Duration, isDebug bool) *Client {
+	clientArest := newClient(name, timeout, isDebug)
+	clientArest.open = opener
+
+	return clientArest
+}
+
+func newClient(port string, timeout time.Duration, isDebug bool) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
 
 	clientArest := &Client{
 		serialPort: nil,
 		isDebug:    isDebug,
 		port:       port,
-		serialMode: serialMode,
 		timeout:    timeout,
 		mutex:      sync.Mutex{},
-		mutexPin:   sync.Mutex{},
-		connected:  atomic.Value{},
 		com: &Com{
-			Res:      make(chan string),
-			Err:      make(chan error),
-			Watchdog: make(chan bool),
+			Res:      make(chan string, 1),
+			Err:      make(chan error, 1),
+			Activity: make(chan bool, 1),
 		},
-		Eventer: gobot.NewEventer(),
-		pins:    atomic.Value{},
 	}
-
-	clientArest.pins.Store(make(map[int]*client.Pin))
-
-	clientArest.AddEvent("connected")
-	clientArest.AddEvent("disconnected")
-	clientArest.AddEvent("reconnected")
-	clientArest.AddEvent("timeout")
-	clientArest.connected.Store(false)
+	clientArest.Protocol = client.NewProtocol(clientArest, isDebug)
 
 	// It permit to try to reconnect on serial if timeout throw from watchdog
-	// It try for ever to reconnect on board
+	// It try to reconnect on board with the reconnect policy
 	if err := clientArest.On("timeout", func(s interface{}) {
-		isReconnected := false
-		for !isReconnected {
-			time.Sleep(1 * time.Millisecond)
-			err := clientArest.Reconnect(context.TODO())
-			if err == nil {
-				isReconnected = true
-			} else {
-				log.Error(err)
-			}
-		}
+		clientArest.StartReconnect(clientArest.reconnect)
 	}); err != nil {
 		panic(err)
 	}
@@ -85,8 +90,26 @@ func NewClient(port string, serialMode *serial.Mode, timeout time.Duration, isDe
 }
 
 // SetSerial permit to set extra serial.Port
+// It's used again on reconnect.
 func (c *Client) SetSerial(s serial.Port) {
 	c.serialPort = s
+	c.open = func() (serial.Port, error) {
+		return s, nil
+	}
+}
+
+// SetFraming permit to set the line terminators and the max line length used to split the serial stream in responses
+// Nil restore the default framing. It need to be called before Connect.
+func (c *Client) SetFraming(framing *Framing) {
+	c.framing = framing
+}
+
+// Framing return the framing used to split the serial stream in responses
+func (c *Client) Framing() *Framing {
+	if c.framing == nil {
+		return DefaultFraming()
+	}
+	return c.framing
 }
 
 // Client permit to get curent serial client
@@ -94,32 +117,47 @@ func (c *Client) Client() serial.Port {
 	return c.serialPort
 }
 
-// Pins return the current pins
-func (c *Client) Pins() map[int]*client.Pin {
-	return c.pins.Load().(map[int]*client.Pin)
+// Send permit to send command to the board throught serial
+// It implement client.Transport interface
+func (c *Client) Send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	if !c.IsConnected() {
+		return nil, client.ErrNotConnected
+	}
+
+	return c.sendLine(ctx, cmd)
 }
 
-// AddPin permit to add pin.
-func (c *Client) AddPin(name int, pin *client.Pin) {
-	c.mutexPin.Lock()
-	defer c.mutexPin.Unlock()
+// sendLine permit to write the command line on serial and wait the response
+func (c *Client) sendLine(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resp, err := c.write(ctx, cmd.String()+"\n\r")
+	if err != nil {
+		return nil, err
+	}
 
-	pins := c.Pins()
-	pins[name] = pin
-	c.pins.Store(pins)
+	return []byte(resp), nil
 }
 
 // Connect start connection to the board
 // It call the root url to check if board is online
 func (c *Client) Connect(ctx context.Context) (err error) {
 
-	if c.connected.Load().(bool) {
+	if c.IsConnected() {
 		return
 	}
 
-	// Create serial port only if not yet setted
+	c.SetConnecting()
+	defer func() {
+		if err != nil {
+			c.SetConnectFailed(err)
+		}
+	}()
+
+	// Open serial port only if not yet opened
 	if c.serialPort == nil {
-		serialPort, err := serial.Open(c.port, c.serialMode)
+		serialPort, err := c.open()
 		if err != nil {
 			return err
 		}
@@ -135,329 +173,101 @@ func (c *Client) Connect(ctx context.Context) (err error) {
 	}
 
 	// Start routine to read serial
-	c.readProcess(ctx)
+	// It's not linked with the context of Connect because of it run until disconnect
+	if c.cancel != nil {
+		c.cancel()
+	}
+	readCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.readProcess(readCtx, c.serialPort)
 
 	// Try connexion
+	// The root url is used because of it return identity and variables
 	time.Sleep(1 * time.Second)
-	_, err = c.write(ctx, "/")
+	body, err := c.sendLine(ctx, client.RootCommand())
 	if err != nil {
 		return err
 	}
+	if _, err = c.HandleInfo(client.RootCommand(), body); err != nil {
+		return err
+	}
 
 	c.Publish("connected", true)
-	c.connected.Store(true)
+	c.SetState(client.StateConnected, "connected")
 
 	return nil
 }
 
 // Disconnect close connecion to the board
+// It stop the reconnect routine if any
 func (c *Client) Disconnect(ctx context.Context) (err error) {
+	c.StopReconnect()
+	c.SetState(client.StateDisconnected, "disconnect")
 
-	c.connected.Store(false)
-	if err = c.serialPort.Close(); err != nil {
-		return err
-	}
-	if err = c.serialPort.ResetInputBuffer(); err != nil {
-		return err
-	}
-	if err = c.serialPort.ResetOutputBuffer(); err != nil {
-		return err
-	}
-
-	c.Publish("disconnected", true)
-
-	return nil
-}
-
-// Reconnect close and start connection to the board
-func (c *Client) Reconnect(ctx context.Context) (err error) {
-	err = c.Disconnect(ctx)
-	if err != nil {
-		return err
-	}
-
-	time.Sleep(1 * time.Second)
-
-	err = c.Connect(ctx)
-	if err != nil {
-		return err
-	}
-
-	// Set pin mode and output
-	for pin, state := range c.Pins() {
-		err = c.SetPinMode(ctx, pin, state.Mode)
-		if err != nil {
-			return err
-		}
-
-		if state.Mode == client.ModeOutput {
-			err = c.DigitalWrite(ctx, pin, state.Value)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	c.Publish("reconnected", true)
-
-	return nil
+	return c.disconnect()
 }
 
-// SetPinMode permit to set pin mode
-func (c *Client) SetPinMode(ctx context.Context, pin int, mode string) (err error) {
-
-	if !c.connected.Load().(bool) {
-		return errors.New("Not connected")
-	}
+// disconnect close the serial port
+// The state is changed by caller.
+func (c *Client) disconnect() (err error) {
 
-	if c.Pins()[pin] == nil {
-		c.AddPin(pin, &client.Pin{})
+	// Stop read routine
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
 	}
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
-
-		if c.isDebug {
-			log.Debugf("Pin: %d, Mode: %s", pin, mode)
+	if c.serialPort != nil {
+		// The port is closed even if purge failed, like when board is unplugged
+		if err = c.serialPort.ResetInputBuffer(); err == nil {
+			err = c.serialPort.ResetOutputBuffer()
 		}
-
-		if mode != client.ModeInput && mode != client.ModeInputPullup && mode != client.ModeOutput {
-			return errors.Errorf("Can't found mode %s", mode)
+		if errClose := c.serialPort.Close(); errClose != nil && err == nil {
+			err = errClose
 		}
-
-		url := fmt.Sprintf("/mode/%d/%s\n\r", pin, mode)
-
-		resp, err := c.write(ctx, url)
+		c.serialPort = nil
 		if err != nil {
 			return err
 		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp)
-		}
-
-		c.Pins()[pin].Mode = mode
-
-		return nil
 	}
-}
-
-// DigitalWrite permit to set level on pin
-func (c *Client) DigitalWrite(ctx context.Context, pin int, level int) (err error) {
-
-	if c.Pins()[pin] == nil {
-		return errors.Errorf("You need to set pin mode on pin %d before use it", pin)
-	}
-	if c.Pins()[pin].Mode != client.ModeOutput {
-		return errors.Errorf("You need to set pin mode as output for pin %d before write on it", pin)
-	}
-	if !c.connected.Load().(bool) {
-		return errors.New("Not connected")
-	}
-
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
-
-		if c.isDebug {
-			log.Debugf("Pin: %d, Level: %d", pin, level)
-		}
-
-		if level != client.LevelHigh && level != client.LevelLow {
-			return errors.Errorf("Can't found level %d", level)
-		}
 
-		url := fmt.Sprintf("/digital/%d/%d\n\r", pin, level)
-
-		resp, err := c.write(ctx, url)
-		if err != nil {
-			return err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp)
-		}
-
-		c.Pins()[pin].Value = level
+	c.Publish("disconnected", true)
 
-		return nil
-	}
+	return nil
 }
 
-// DigitalRead permit to read level from pin
-func (c *Client) DigitalRead(ctx context.Context, pin int) (level int, err error) {
-	if c.Pins()[pin] == nil {
-		return 0, errors.Errorf("You need to set pin mode on pin %d before use it", pin)
-	}
-	if c.Pins()[pin].Mode != client.ModeInput && c.Pins()[pin].Mode != client.ModeInputPullup {
-		return 0, errors.Errorf("You need to set pin mode as input or input_pullup for pin %d before read on it", pin)
-	}
-	if !c.connected.Load().(bool) {
-		return level, errors.New("Not connected")
-	}
-
-	select {
-	case <-ctx.Done():
-		return level, ctx.Err()
-	default:
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
-
-		if c.isDebug {
-			log.Debugf("Pin: %d", pin)
-		}
-
-		url := fmt.Sprintf("/digital/%d\n\r", pin)
-		data := make(map[string]interface{})
-
-		resp, err := c.write(ctx, url)
-		if err != nil {
-			return level, err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp read: %s", resp)
-		}
-
-		err = json.Unmarshal([]byte(resp), &data)
-		if err != nil {
-			return level, err
-		}
+// Reconnect close and start connection to the board
+func (c *Client) Reconnect(ctx context.Context) (err error) {
+	c.StopReconnect()
+	c.SetState(client.StateReconnecting, "reconnect")
 
-		return int(data["return_value"].(float64)), nil
-	}
+	return c.reconnect(ctx)
 }
 
-// ReadValue permit to read user variable
-func (c *Client) ReadValue(ctx context.Context, name string) (value interface{}, err error) {
-	if !c.connected.Load().(bool) {
-		return value, errors.New("Not connected")
+// reconnect close and start connection to the board, then restore pins
+func (c *Client) reconnect(ctx context.Context) (err error) {
+	err = c.disconnect()
+	if err != nil {
+		return err
 	}
 
 	select {
 	case <-ctx.Done():
-		return value, ctx.Err()
-	default:
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
-
-		if c.isDebug {
-			log.Debugf("Value name: %s", name)
-		}
-
-		url := fmt.Sprintf("/%s\n\r", name)
-		data := make(map[string]interface{})
-
-		resp, err := c.write(ctx, url)
-		if err != nil {
-			return nil, err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp)
-		}
-
-		err = json.Unmarshal([]byte(resp), &data)
-		if err != nil {
-			return nil, err
-		}
-
-		if temp, ok := data[name]; ok {
-			value = temp
-		} else {
-			err = errors.Errorf("Variable %s not found", name)
-		}
-
-		return value, err
-	}
-}
-
-// ReadValues permit to read user variable
-func (c *Client) ReadValues(ctx context.Context) (values map[string]interface{}, err error) {
-	if !c.connected.Load().(bool) {
-		return values, errors.New("Not connected")
+		return ctx.Err()
+	case <-time.After(1 * time.Second):
 	}
 
-	select {
-	case <-ctx.Done():
-		return values, ctx.Err()
-	default:
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
-		url := "/\n\r"
-		data := make(map[string]interface{})
-
-		resp, err := c.write(ctx, url)
-		if err != nil {
-			return nil, err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp)
-		}
-
-		err = json.Unmarshal([]byte(resp), &data)
-		if err != nil {
-			return nil, err
-		}
-
-		if temp, ok := data["variables"]; ok {
-			values = temp.(map[string]interface{})
-		} else {
-			err = errors.Errorf("No variable found")
-		}
-
-		return values, err
+	err = c.Connect(ctx)
+	if err != nil {
+		return err
 	}
-}
 
-// CallFunction permit to call user function
-func (c *Client) CallFunction(ctx context.Context, name string, param string) (value int, err error) {
-	if !c.connected.Load().(bool) {
-		return value, errors.New("Not connected")
+	// Set pin mode and output
+	if err = c.RestorePins(ctx); err != nil {
+		return err
 	}
 
-	select {
-	case <-ctx.Done():
-		return value, ctx.Err()
-	default:
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
-
-		if c.isDebug {
-			log.Debugf("Function: %s, param: %s", name, param)
-		}
-
-		url := fmt.Sprintf("/%s?params=%s\n\r", name, param)
-		data := make(map[string]interface{})
-
-		resp, err := c.write(ctx, url)
-		if err != nil {
-			return value, err
-		}
-
-		if c.isDebug {
-			log.Debugf("Resp: %s", resp)
-		}
-
-		err = json.Unmarshal([]byte(resp), &data)
-		if err != nil {
-			return value, err
-		}
-
-		if temp, ok := data["return_value"]; ok {
-			value = int(temp.(float64))
-		} else {
-			return value, errors.Errorf("Function %s not found", name)
-		}
+	c.Publish("reconnected", true)
 
-		return value, nil
-	}
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	"github.com/jarcoal/httpmock"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+	"go.bug.st/serial"
 )
 
 type ArestTestSuite struct {
@@ -48,7 +50,25 @@ func (s *ArestTestSuite) TestConnect() {
 
 	err := s.client.Connect(context.Background())
 	assert.NoError(s.T(), err)
-	assert.True(s.T(), s.client.connected.Load().(bool))
+	assert.True(s.T(), s.client.IsConnected())
+
+	info, err := s.client.Info(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "002", info.ID)
+	assert.Equal(s.T(), "TFP", info.Name)
+	assert.Equal(s.T(), "arduino", info.Hardware)
+	assert.Equal(s.T(), []string{"isRebooted"}, info.Variables)
+}
+
+func (s *ArestTestSuite) TestConnectExpectedInfo() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.client.SetExpectedInfo(&client.BoardInfo{Name: "pool"})
+	err := s.client.Connect(context.Background())
+	assert.Error(s.T(), err)
+	assert.IsType(s.T(), &client.BoardMismatchError{}, err)
+	assert.False(s.T(), s.client.IsConnected())
 }
 
 func (s *ArestTestSuite) TestDisconnect() {
@@ -57,7 +77,10 @@ func (s *ArestTestSuite) TestDisconnect() {
 
 	err := s.client.Disconnect(context.Background())
 	assert.NoError(s.T(), err)
-	assert.False(s.T(), s.client.connected.Load().(bool))
+	assert.False(s.T(), s.client.IsConnected())
+
+	_, err = s.client.ReadValue(context.Background(), "isRebooted")
+	assert.ErrorIs(s.T(), err, client.ErrNotConnected)
 }
 
 func (s *ArestTestSuite) TestReconnect() {
@@ -66,7 +89,7 @@ func (s *ArestTestSuite) TestReconnect() {
 
 	err := s.client.Reconnect(context.Background())
 	assert.NoError(s.T(), err)
-	assert.True(s.T(), s.client.connected.Load().(bool))
+	assert.True(s.T(), s.client.IsConnected())
 }
 
 func (s *ArestTestSuite) TestSetMode() {
@@ -149,6 +172,60 @@ func (s *ArestTestSuite) TestDigitalRead() {
 	assert.Equal(s.T(), client.LevelHigh, level)
 }
 
+func (s *ArestTestSuite) TestAnalogWrite() {
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Return error if pin is not yet setted
+	err := s.client.AnalogWrite(context.Background(), 3, 127)
+	assert.Error(s.T(), err)
+
+	// Return error if pin is not output mode
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeInput); err != nil {
+		s.T().Fatal(err)
+	}
+	err = s.client.AnalogWrite(context.Background(), 3, 127)
+	assert.Error(s.T(), err)
+
+	// Normal use case
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	err = s.client.AnalogWrite(context.Background(), 3, 127)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 127, s.client.Pins()[3].Duty)
+	assert.True(s.T(), s.client.Pins()[3].IsPwm)
+}
+
+func (s *ArestTestSuite) TestAnalogRead() {
+
+	var err error
+
+	// Error when not yet connected
+	_, err = s.client.AnalogRead(context.Background(), 0)
+	assert.Error(s.T(), err)
+
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	fixture := map[string]interface{}{
+		"return_value": 512,
+	}
+	s.client.Client().(*MockSerial).ReadData, err = json.Marshal(fixture)
+	if err != nil {
+		panic(err)
+	}
+
+	value, err := s.client.AnalogRead(context.Background(), 0)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 512, value)
+}
+
 func (s *ArestTestSuite) TestReadValue() {
 
 	if err := s.client.Connect(context.Background()); err != nil {
@@ -205,6 +282,7 @@ func (s *ArestTestSuite) TestCallFunction() {
 	var err error
 	fixture := map[string]interface{}{
 		"return_value": 1,
+		"message":      "Rebooted acknowledged",
 	}
 	s.client.Client().(*MockSerial).ReadData, err = json.Marshal(fixture)
 	if err != nil {
@@ -215,8 +293,74 @@ func (s *ArestTestSuite) TestCallFunction() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), 1, resp)
 
+	res, err := s.client.CallFunctionWithResult(context.Background(), "acknoledgeRebooted", "test")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, res.ReturnValue)
+	assert.Equal(s.T(), "Rebooted acknowledged", res.Message)
+
 	// Bad
-	s.client.Client().(*MockSerial).ReadData = make([]byte, 0)
+	s.client.Client().(*MockSerial).ReadData = []byte(`{"return_value": "bad"}`)
 	_, err = s.client.CallFunction(context.Background(), "bad", "test")
 	assert.Error(s.T(), err)
 }
+
+func (s *ArestTestSuite) TestNoise() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	// Boot banner before the first response
+	s.client.Client().(*MockSerial).ReadData = []byte("aREST boot\r\n\x00\xff{\"variables\": {\"isRebooted\": true}, \"id\": \"002\", \"name\": \"TFP\", \"hardware\": \"arduino\", \"connected\": true}")
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	values, err := s.client.ReadValues(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), map[string]interface{}{"isRebooted": true}, values)
+
+	// Two responses in one read, the second is not expected
+	s.client.Client().(*MockSerial).ReadData = []byte("{\"return_value\": 1}\r\n{\"return_value\": 0}")
+	level, err := s.client.AnalogRead(context.Background(), 1)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, level)
+
+	// Late response is discarded
+	s.client.Client().(*MockSerial).ReadData = []byte(`{"return_value": 2}`)
+	level, err = s.client.AnalogRead(context.Background(), 1)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, level)
+}
+
+func (s *ArestTestSuite) TestTimeout() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	// Default timeout, so board that never answer not block
+	assert.Equal(s.T(), DefaultTimeout, NewClient("/dev/null", &serial.Mode{}, 0, false).timeout)
+
+	c := NewClient("/dev/null", &serial.Mode{}, 200*time.Millisecond, true)
+	mock := NewMockSerial()
+	mock.(*MockSerial).ReadData = []byte(`{"variables": {}, "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`)
+	c.SetSerial(mock)
+	c.SetReconnectPolicy(&client.ReconnectPolicy{InitialInterval: 1 * time.Hour})
+	if err := c.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	defer c.Disconnect(context.Background())
+
+	isTimeout := make(chan bool, 1)
+	if err := c.On("timeout", func(data interface{}) {
+		isTimeout <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Only noise, no response
+	mock.(*MockSerial).ReadData = []byte("garbage")
+	_, err := c.AnalogRead(context.Background(), 1)
+	assert.ErrorIs(s.T(), err, client.ErrTimeout)
+	select {
+	case <-isTimeout:
+	case <-time.After(1 * time.Second):
+		s.T().Fatal("timeout event not published")
+	}
+}
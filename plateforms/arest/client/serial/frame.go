@@ -0,0 +1,168 @@
+package serialClient
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultMaxLineLength is the max line length kept when no terminator is read
+const DefaultMaxLineLength = 16384
+
+// DefaultTerminators is the line terminators used by aREST firmware, Serial.println send \r\n
+var DefaultTerminators = []string{"\r\n", "\n", "\r"}
+
+// Framing permit to set how the serial stream is split in responses
+type Framing struct {
+	// Terminators are the line terminators, the first found in stream end the line
+	Terminators []string
+
+	// MaxLineLength is the max line length, longer line is discarded until next terminator
+	MaxLineLength int
+}
+
+// DefaultFraming return the framing used when no framing is set
+func DefaultFraming() *Framing {
+	return &Framing{
+		Terminators:   DefaultTerminators,
+		MaxLineLength: DefaultMaxLineLength,
+	}
+}
+
+// frameScanner split the serial stream in JSON responses
+// Lines that not contain JSON object, like firmware boot banner, are discarded. Text before the JSON object is discarded too,
+// so the scanner resynchronize on next response after garbage.
+type frameScanner struct {
+	terminators   [][]byte
+	maxLineLength int
+	isDebug       bool
+
+	buffer []byte
+	// It permit to discard the end of line that exceed maxLineLength
+	isDiscarding bool
+}
+
+// newFrameScanner permit to initialize new frame scanner
+func newFrameScanner(framing *Framing, isDebug bool) *frameScanner {
+	if framing == nil {
+		framing = DefaultFraming()
+	}
+
+	s := &frameScanner{
+		maxLineLength: framing.MaxLineLength,
+		isDebug:       isDebug,
+	}
+	for _, terminator := range framing.Terminators {
+		if terminator != "" {
+			s.terminators = append(s.terminators, []byte(terminator))
+		}
+	}
+	if len(s.terminators) == 0 {
+		for _, terminator := range DefaultTerminators {
+			s.terminators = append(s.terminators, []byte(terminator))
+		}
+	}
+	if s.maxLineLength <= 0 {
+		s.maxLineLength = DefaultMaxLineLength
+	}
+
+	return s
+}
+
+// Scan add data read from serial and return the complete JSON responses, in order
+func (s *frameScanner) Scan(data []byte) (frames []string) {
+	s.buffer = append(s.buffer, data...)
+
+	for {
+		index, length := s.nextTerminator()
+		if index < 0 {
+			break
+		}
+		line := s.buffer[:index]
+		s.buffer = s.buffer[index+length:]
+
+		if s.isDiscarding {
+			s.isDiscarding = false
+			continue
+		}
+		frames = append(frames, s.decodeLine(line)...)
+	}
+
+	// Resynchronize on next line if terminator is missing
+	if len(s.buffer) > s.maxLineLength {
+		s.discard("line too long", s.buffer)
+		s.buffer = nil
+		s.isDiscarding = true
+	}
+
+	// Not keep reference on read buffer
+	if len(s.buffer) == 0 {
+		s.buffer = nil
+	}
+
+	return frames
+}
+
+// nextTerminator return the position of the first terminator in buffer, the longest one if many start at same position
+func (s *frameScanner) nextTerminator() (index int, length int) {
+	index = -1
+	for _, terminator := range s.terminators {
+		i := bytes.Index(s.buffer, terminator)
+		if i < 0 {
+			continue
+		}
+		if index < 0 || i < index || (i == index && len(terminator) > length) {
+			index = i
+			length = len(terminator)
+		}
+	}
+
+	return index, length
+}
+
+// decodeLine return the JSON objects from line
+// Many objects can be on the same line if terminator is lost, the text around them is discarded.
+func (s *frameScanner) decodeLine(line []byte) (frames []string) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+
+	for len(line) > 0 {
+		start := bytes.IndexByte(line, '{')
+		if start < 0 {
+			s.discard("not JSON", line)
+			return frames
+		}
+		if start > 0 {
+			s.discard("not JSON", line[:start])
+			line = line[start:]
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		var object json.RawMessage
+		if err := decoder.Decode(&object); err != nil {
+			if err == io.ErrUnexpectedEOF || err == io.EOF {
+				s.discard("truncated JSON", line)
+				return frames
+			}
+			// Garbage that start with {, try with next object
+			s.discard("bad JSON", line[:1])
+			line = line[1:]
+			continue
+		}
+		frames = append(frames, string(object))
+		line = bytes.TrimSpace(line[decoder.InputOffset():])
+	}
+
+	return frames
+}
+
+// discard log the discarded data
+func (s *frameScanner) discard(reason string, data []byte) {
+	if s.isDebug {
+		log.Debugf("Discard serial data (%s): %q", reason, data)
+	}
+}
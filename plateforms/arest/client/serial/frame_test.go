@@ -0,0 +1,87 @@
+package serialClient
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameScanner(t *testing.T) {
+	testCases := []struct {
+		name    string
+		framing *Framing
+		reads   []string
+		frames  []string
+	}{
+		{
+			name:   "one response",
+			reads:  []string{"{\"id\": \"002\"}\r\n"},
+			frames: []string{`{"id": "002"}`},
+		},
+		{
+			name:   "response split across reads",
+			reads:  []string{"{\"id\": ", "\"002\"}\r", "\n{\"return", "_value\": 1}\r\n"},
+			frames: []string{`{"id": "002"}`, `{"return_value": 1}`},
+		},
+		{
+			name:   "two responses in one read",
+			reads:  []string{"{\"id\": \"002\"}\r\n{\"id\": \"003\"}\r\n"},
+			frames: []string{`{"id": "002"}`, `{"id": "003"}`},
+		},
+		{
+			name:   "boot banner",
+			reads:  []string{"\x00\xffaREST boot\r\nWiFi connected\r\n{\"id\": \"002\"}\r\n"},
+			frames: []string{`{"id": "002"}`},
+		},
+		{
+			name:   "garbage before response",
+			reads:  []string{"\x12\x34garbage{\"id\": \"002\"}\r\n"},
+			frames: []string{`{"id": "002"}`},
+		},
+		{
+			name:   "lost terminator",
+			reads:  []string{"{\"id\": \"002\"}{\"id\": \"003\"} trailing\r\n"},
+			frames: []string{`{"id": "002"}`, `{"id": "003"}`},
+		},
+		{
+			name:   "bad JSON then response",
+			reads:  []string{"{bad{\"id\": \"002\"}\r\n", "{\"id\": \r\n{\"id\": \"003\"}\n"},
+			frames: []string{`{"id": "002"}`, `{"id": "003"}`},
+		},
+		{
+			name:    "line too long",
+			framing: &Framing{MaxLineLength: 20},
+			reads:   []string{"{\"variables\": {\"temperature\"", ": 21.5}}\r\n{\"id\": \"002\"}\r\n"},
+			frames:  []string{`{"id": "002"}`},
+		},
+		{
+			name:    "custom terminator",
+			framing: &Framing{Terminators: []string{";"}},
+			reads:   []string{"{\"id\": \"002\"}\r\n;{\"id\": \"003\"};"},
+			frames:  []string{`{"id": "002"}`, `{"id": "003"}`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		scanner := newFrameScanner(testCase.framing, true)
+		frames := make([]string, 0)
+		for _, read := range testCase.reads {
+			frames = append(frames, scanner.Scan([]byte(read))...)
+		}
+		assert.Equal(t, testCase.frames, frames, testCase.name)
+	}
+}
+
+func TestFrameScannerPartial(t *testing.T) {
+	scanner := newFrameScanner(nil, false)
+
+	// Wait terminator
+	assert.Empty(t, scanner.Scan([]byte(`{"id": "002"}`)))
+	assert.Equal(t, []string{`{"id": "002"}`}, scanner.Scan([]byte("\r\n")))
+
+	// Not keep the read buffer
+	assert.Empty(t, scanner.Scan([]byte(strings.Repeat("x", 10))))
+	assert.Nil(t, scanner.Scan([]byte("\n")))
+	assert.Nil(t, scanner.buffer)
+}
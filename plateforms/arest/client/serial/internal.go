@@ -2,108 +2,111 @@ package serialClient
 
 import (
 	"context"
-	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.bug.st/serial"
 )
 
 // Com permit communication with read routine
 type Com struct {
-	Err      chan error
-	Res      chan string
-	Watchdog chan bool
-}
-
-func (c *Client) readProcess(ctx context.Context) {
-
-	// Channel to sync watchdog with read routine
-	chPing := make(chan bool)
-	chEnd := make(chan bool)
-
-	// Run watchdog routine when write
-	go func() {
-		for {
-			// Start watchdog when write is called
-			<-c.com.Watchdog
+	Err chan error
+	Res chan string
 
-			timer := time.NewTicker(c.timeout)
-			stopWatchdogLoop := false
+	// Activity is notified on each read, it reset the response timeout
+	Activity chan bool
 
-			for !stopWatchdogLoop {
-				select {
-				case <-ctx.Done():
-					// context done
-					if c.isDebug {
-						log.Debugf("Watchdog exit because of context done")
-					}
-					return
-				case <-chPing:
-					// Reset timer
-					timer = time.NewTicker(c.timeout)
-				case <-timer.C:
-					// Timeout
-					if c.isDebug {
-						log.Debug("Watchdog detect timeout, we close connexion")
-					}
-					c.Publish("timeout", true)
-					return
-				case <-chEnd:
-					// read complet
-					stopWatchdogLoop = true
-				}
+	// It permit to deliver response only when command wait it
+	isWaiting atomic.Bool
+}
 
-				time.Sleep(1 * time.Millisecond)
-			}
-		}
-	}()
+// readProcess read the serial stream and deliver responses to the pending write
+// Responses without pending write, like firmware boot banner or late response after timeout, are discarded.
+// When read failed, like when board is unplugged, it try to reconnect with the reconnect policy.
+func (c *Client) readProcess(ctx context.Context, port serial.Port) {
+	scanner := newFrameScanner(c.framing, c.isDebug)
 
-	// Read routine
 	go func() {
 		buffer := make([]byte, 4096)
-		var resp strings.Builder
-		loop := true
 
 		for {
-			for loop {
-				select {
-				case <-ctx.Done():
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			n, err := port.Read(buffer)
+			if err != nil {
+				if ctx.Err() != nil {
+					// Port closed by disconnect
 					return
+				}
+				if c.isDebug {
+					log.Debugf("Read error on %s: %s", c.port, err.Error())
+				}
+				// Release the pending write
+				select {
+				case c.com.Err <- err:
 				default:
-					n, err := c.serialPort.Read(buffer)
-					if err != nil {
-						c.com.Err <- err
-						return
-					}
-					if n == 0 {
-						loop = false
-						break
-					}
-					resp.Write(buffer[:n])
-
-					if strings.Contains(string(buffer[:n]), "\n") {
-						loop = false
-						break
-					}
-
-					chPing <- true
 				}
+				c.StartReconnect(c.reconnect)
+				return
+			}
+			if n == 0 {
+				continue
 			}
 
-			chEnd <- true
-			loop = true
-			c.com.Res <- resp.String()
+			select {
+			case c.com.Activity <- true:
+			default:
+			}
 
-			resp.Reset()
+			for _, frame := range scanner.Scan(buffer[:n]) {
+				c.deliver(frame)
+			}
 		}
 	}()
 }
 
+// deliver send the response to the pending write
+func (c *Client) deliver(frame string) {
+	if !c.com.isWaiting.CompareAndSwap(true, false) {
+		if c.isDebug {
+			log.Debugf("Discard unexpected response: %s", frame)
+		}
+		return
+	}
+
+	c.com.Res <- frame
+}
+
 // write permit to sync the read/write on serial
+// The timeout is reset each time data is read, so long response not timeout.
 func (c *Client) write(ctx context.Context, url string) (res string, err error) {
 
-	// Start watchdog
-	c.com.Watchdog <- true
+	if c.serialPort == nil {
+		return "", client.ErrNotConnected
+	}
+
+	// Clean state of previous command
+	select {
+	case <-c.com.Res:
+	default:
+	}
+	select {
+	case <-c.com.Err:
+	default:
+	}
+	select {
+	case <-c.com.Activity:
+	default:
+	}
+	c.com.isWaiting.Store(true)
+	defer c.com.isWaiting.Store(false)
 
 	// Write query on serial
 	_, err = c.serialPort.Write([]byte(url))
@@ -112,13 +115,31 @@ func (c *Client) write(ctx context.Context, url string) (res string, err error)
 	}
 
 	// Wait result
-	select {
-	case <-ctx.Done():
-		return "", ctx.Err()
-	case err := <-c.com.Err:
-		return "", err
-	case res = <-c.com.Res:
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case err := <-c.com.Err:
+			return "", err
+		case res = <-c.com.Res:
+			return res, nil
+		case <-c.com.Activity:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(c.timeout)
+		case <-timer.C:
+			if c.isDebug {
+				log.Debug("Watchdog detect timeout, we close connexion")
+			}
+			c.Publish("timeout", true)
+			return "", errors.Wrapf(client.ErrTimeout, "No response from %s after %s", c.port, c.timeout)
+		}
 	}
-
-	return res, nil
 }
@@ -10,6 +10,7 @@ import (
 func MockSerialClient() *Client {
 
 	mock := NewMockSerial()
+	mock.(*MockSerial).ReadData = []byte(`{"variables": {"isRebooted": false}, "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`)
 	client := NewClient("/dev/null", &serial.Mode{}, 100*time.Second, true)
 	client.SetSerial(mock)
 
@@ -39,6 +40,7 @@ type MockSerial struct {
 	ReadData  []byte
 	readData  []byte
 	WriteData []byte
+	written   chan struct{}
 	mtx       sync.Mutex
 }
 
@@ -70,85 +72,50 @@ func (m *MockSerial) Close() error {
 }
 
 func (m *MockSerial) InitRead() {
-	m.mtx.Lock()
+	m.written = make(chan struct{}, 1)
+
 	m.read = func(p []byte) (n int, err error) {
 
 		// Simulate wait data
-		m.mtx.Lock()
+		<-m.written
 
-		if len(m.readData) == 0 {
-			return 0, nil
-		}
-
-		n = len(m.readData)
-		for i, b := range m.readData {
-			if i < len(p) {
-				p[i] = b
-			} else {
-				n = i
-				break
-			}
-		}
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
 
+		n = copy(p, m.readData)
 		m.readData = m.readData[n:]
-		m.mtx.Unlock()
+		if len(m.readData) > 0 {
+			m.signal()
+		}
 
 		return
 
 	}
 
 	m.write = func(p []byte) (n int, err error) {
-		m.readData = m.ReadData
-
-		go func() {
-			m.mtx.Unlock()
-		}()
+		// aREST firmware send response with println
+		m.mtx.Lock()
+		m.readData = append(append([]byte{}, m.ReadData...), '\r', '\n')
+		m.mtx.Unlock()
+		m.signal()
 
 		return 0, nil
 	}
 }
 
+// signal wake up the pending read
+func (m *MockSerial) signal() {
+	select {
+	case m.written <- struct{}{}:
+	default:
+	}
+}
+
 func NewMockSerial() serial.Port {
 	m := &MockSerial{
 		close: func() error { return nil },
 	}
-
-	m.mtx.Lock()
-	m.read = func(p []byte) (n int, err error) {
-
-		// Simulate wait data
-		m.mtx.Lock()
-
-		if len(m.readData) == 0 {
-			return 0, nil
-		}
-
-		n = len(m.readData)
-		for i, b := range m.readData {
-			if i < len(p) {
-				p[i] = b
-			} else {
-				n = i
-				break
-			}
-		}
-
-		m.readData = m.readData[n:]
-		m.mtx.Unlock()
-
-		return
-
-	}
-
-	m.write = func(p []byte) (n int, err error) {
-		m.readData = m.ReadData
-
-		go func() {
-			m.mtx.Unlock()
-		}()
-
-		return 0, nil
-	}
+	m.InitRead()
 
 	return m
 }
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDegradedThreshold is the number of consecutive command errors before the board is degraded
+const DefaultDegradedThreshold = 3
+
+// State is the connection state of the board
+type State int
+
+const (
+	// StateDisconnected is the state before Connect, or after Disconnect
+	StateDisconnected State = iota
+
+	// StateConnecting is the state while Connect run
+	StateConnecting
+
+	// StateConnected is the state when board answer
+	StateConnected
+
+	// StateDegraded is the state when commands failed consecutively but the connection is still alive, see SetDegradedThreshold
+	StateDegraded
+
+	// StateReconnecting is the state while the reconnect routine run
+	StateReconnecting
+
+	// StateFailed is the state when the reconnect policy give up, only Connect or Reconnect leave it
+	StateFailed
+)
+
+// String return the state name
+func (s State) String() string {
+	switch s {
+	case StateDisconnected:
+		return "disconnected"
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateDegraded:
+		return "degraded"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// IsConnected return true if commands can be sent on board, so connected or degraded
+func (s State) IsConnected() bool {
+	return s == StateConnected || s == StateDegraded
+}
+
+// StateChange is published with state-changed event
+type StateChange struct {
+	Old    State
+	New    State
+	Reason string
+}
+
+// String return human readable state change
+func (c *StateChange) String() string {
+	return fmt.Sprintf("%s -> %s: %s", c.Old, c.New, c.Reason)
+}
+
+// stateMachine keep the connection state and the consecutive errors
+type stateMachine struct {
+	mutex     sync.Mutex
+	state     State
+	errors    int
+	threshold int
+}
+
+// State return the current connection state
+func (p *Protocol) State() State {
+	p.state.mutex.Lock()
+	defer p.state.mutex.Unlock()
+	return p.state.state
+}
+
+// IsConnected return true if the board is connected or degraded
+func (p *Protocol) IsConnected() bool {
+	return p.State().IsConnected()
+}
+
+// SetState permit to transports to change the connection state
+// It publish state-changed event with StateChange if the state change.
+func (p *Protocol) SetState(state State, reason string) {
+	p.state.mutex.Lock()
+	old := p.state.state
+	p.state.state = state
+	if !state.IsConnected() || !old.IsConnected() {
+		p.state.errors = 0
+	}
+	p.state.mutex.Unlock()
+
+	if old != state {
+		p.Publish("state-changed", &StateChange{
+			Old:    old,
+			New:    state,
+			Reason: reason,
+		})
+	}
+}
+
+// SetConnecting permit to transports to mark the board as connecting when Connect start
+// The state stay reconnecting if Connect is called by the reconnect routine, so each try not change the state.
+func (p *Protocol) SetConnecting() {
+	p.state.mutex.Lock()
+	isReconnecting := p.state.state == StateReconnecting
+	p.state.mutex.Unlock()
+
+	if !isReconnecting {
+		p.SetState(StateConnecting, "connect")
+	}
+}
+
+// SetConnectFailed permit to transports to mark the board as disconnected when Connect failed
+// The state stay reconnecting while the reconnect routine run, it try again with the reconnect policy.
+func (p *Protocol) SetConnectFailed(err error) {
+	if p.IsReconnecting() {
+		p.SetState(StateReconnecting, err.Error())
+		return
+	}
+	p.SetState(StateDisconnected, err.Error())
+}
+
+// SetDegradedThreshold permit to set the number of consecutive command errors before the board is degraded
+// The board come back to connected on the first command that succeed. 0 disable the degraded state.
+func (p *Protocol) SetDegradedThreshold(threshold int) {
+	p.state.mutex.Lock()
+	defer p.state.mutex.Unlock()
+	p.state.threshold = threshold
+}
+
+// DegradedThreshold return the number of consecutive command errors before the board is degraded
+func (p *Protocol) DegradedThreshold() int {
+	p.state.mutex.Lock()
+	defer p.state.mutex.Unlock()
+	return p.state.threshold
+}
+
+// handleResponseResult count the decode result of board response
+// Bad response, like truncated or garbage JSON, is counted as command error. Other errors, like variable not found,
+// come from valid response so the board is alive.
+func (p *Protocol) handleResponseResult(err error) {
+	if err != nil && !errors.Is(err, ErrProtocol) {
+		err = nil
+	}
+	p.handleCommandResult(err)
+}
+
+// handleCommandResult count the consecutive command errors to move board between connected and degraded
+// The errors not linked with board, like not connected or cancelled context, are ignored.
+func (p *Protocol) handleCommandResult(err error) {
+	if err != nil && (errors.Is(err, ErrNotConnected) || errors.Is(err, context.Canceled)) {
+		return
+	}
+
+	p.state.mutex.Lock()
+	state := p.state.state
+	if err == nil {
+		p.state.errors = 0
+	} else {
+		p.state.errors++
+	}
+	isDegraded := err != nil && state == StateConnected && p.state.threshold > 0 && p.state.errors >= p.state.threshold
+	isRecovered := err == nil && state == StateDegraded
+	errorCount := p.state.errors
+	p.state.mutex.Unlock()
+
+	switch {
+	case isDegraded:
+		p.SetState(StateDegraded, fmt.Sprintf("%d consecutive errors, last: %s", errorCount, err.Error()))
+	case isRecovered:
+		p.SetState(StateConnected, "command succeeded")
+	}
+}
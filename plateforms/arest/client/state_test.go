@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFailingTransport struct {
+	mutex sync.Mutex
+	err   error
+	body  []byte
+}
+
+func (m *mockFailingTransport) Send(ctx context.Context, cmd *Command) (body []byte, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.body != nil {
+		return m.body, nil
+	}
+	return []byte(`{"return_value": 1}`), nil
+}
+
+func (m *mockFailingTransport) setBody(body []byte) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.body = body
+}
+
+func (m *mockFailingTransport) setError(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.err = err
+}
+
+func TestStateString(t *testing.T) {
+	assert.Equal(t, "disconnected", StateDisconnected.String())
+	assert.Equal(t, "degraded", StateDegraded.String())
+	assert.Equal(t, "failed", StateFailed.String())
+	assert.Equal(t, "State(42)", State(42).String())
+
+	assert.True(t, StateConnected.IsConnected())
+	assert.True(t, StateDegraded.IsConnected())
+	assert.False(t, StateReconnecting.IsConnected())
+}
+
+func TestStateChanged(t *testing.T) {
+	p := NewProtocol(newMockTransport(), false)
+	assert.Equal(t, StateDisconnected, p.State())
+
+	changes := make(chan *StateChange, 10)
+	if err := p.On("state-changed", func(data interface{}) {
+		changes <- data.(*StateChange)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	p.SetState(StateConnecting, "connect")
+	p.SetState(StateConnected, "connected")
+	// Same state is not published
+	p.SetState(StateConnected, "connected")
+	assert.True(t, p.IsConnected())
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, &StateChange{Old: StateDisconnected, New: StateConnecting, Reason: "connect"}, change)
+	case <-time.After(1 * time.Second):
+		t.Fatal("state-changed event not published")
+	}
+	select {
+	case change := <-changes:
+		assert.Equal(t, &StateChange{Old: StateConnecting, New: StateConnected, Reason: "connected"}, change)
+	case <-time.After(1 * time.Second):
+		t.Fatal("state-changed event not published")
+	}
+	select {
+	case change := <-changes:
+		t.Fatalf("unexpected state change %s", change)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStateDegraded(t *testing.T) {
+	transport := &mockFailingTransport{}
+	p := NewProtocol(transport, false)
+	ctx := context.Background()
+	assert.Equal(t, DefaultDegradedThreshold, p.DegradedThreshold())
+	p.SetDegradedThreshold(2)
+	p.SetState(StateConnected, "connected")
+
+	// Degraded after consecutive errors
+	transport.setError(errors.New("bad gateway"))
+	_, err := p.AnalogRead(ctx, 1)
+	assert.Error(t, err)
+	assert.Equal(t, StateConnected, p.State())
+	_, err = p.AnalogRead(ctx, 1)
+	assert.Error(t, err)
+	assert.Equal(t, StateDegraded, p.State())
+	assert.True(t, p.IsConnected())
+
+	// Errors not linked with board are ignored
+	transport.setError(ErrNotConnected)
+	_, _ = p.AnalogRead(ctx, 1)
+	assert.Equal(t, StateDegraded, p.State())
+
+	// Connected on first success
+	transport.setError(nil)
+	_, err = p.AnalogRead(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, StateConnected, p.State())
+
+	// Success reset the error count
+	transport.setError(errors.New("bad gateway"))
+	_, _ = p.AnalogRead(ctx, 1)
+	transport.setError(nil)
+	_, _ = p.AnalogRead(ctx, 1)
+	transport.setError(errors.New("bad gateway"))
+	_, _ = p.AnalogRead(ctx, 1)
+	assert.Equal(t, StateConnected, p.State())
+
+	// Disabled
+	p.SetDegradedThreshold(0)
+	for i := 0; i < 5; i++ {
+		_, _ = p.AnalogRead(ctx, 1)
+	}
+	assert.Equal(t, StateConnected, p.State())
+}
+
+func TestStateDegradedBadResponse(t *testing.T) {
+	transport := &mockFailingTransport{}
+	p := NewProtocol(transport, false)
+	ctx := context.Background()
+	p.SetDegradedThreshold(2)
+	p.SetState(StateConnected, "connected")
+
+	// Board answer with truncated JSON
+	transport.setBody([]byte(`{"return_val`))
+	_, err := p.AnalogRead(ctx, 1)
+	assert.ErrorIs(t, err, ErrProtocol)
+	assert.Equal(t, StateConnected, p.State())
+	_, err = p.ReadValue(ctx, "temperature")
+	assert.ErrorIs(t, err, ErrProtocol)
+	assert.Equal(t, StateDegraded, p.State())
+
+	// Valid response, even with error, mean board is alive
+	transport.setBody([]byte(`{"variables": {"humidity": 10}, "id": "002", "name": "TFP", "hardware": "arduino", "connected": true}`))
+	_, err = p.ReadValue(ctx, "temperature")
+	assert.ErrorIs(t, err, ErrVariableNotFound)
+	assert.Equal(t, StateConnected, p.State())
+}
+
+func TestStateReconnect(t *testing.T) {
+	p := NewProtocol(newMockTransport(), false)
+	p.SetReconnectPolicy(&ReconnectPolicy{InitialInterval: 10 * time.Millisecond, MaxAttempts: 2})
+	p.SetState(StateConnected, "connected")
+
+	// Reconnecting until give up
+	isGiveUp := make(chan bool, 1)
+	if err := p.On("give-up", func(data interface{}) {
+		isGiveUp <- true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	p.StartReconnect(func(ctx context.Context) error {
+		return errors.New("board unplugged")
+	})
+	assert.Equal(t, StateReconnecting, p.State())
+	select {
+	case <-isGiveUp:
+	case <-time.After(1 * time.Second):
+		t.Fatal("give-up event not published")
+	}
+	assert.Equal(t, StateFailed, p.State())
+
+	// Transport set connected when reconnect succeed
+	p.StartReconnect(func(ctx context.Context) error {
+		p.SetState(StateConnected, "connected")
+		return nil
+	})
+	assert.Eventually(t, func() bool { return p.State() == StateConnected }, time.Second, 10*time.Millisecond)
+}
+
+func TestStateConnectWhileReconnect(t *testing.T) {
+	p := NewProtocol(newMockTransport(), false)
+	p.SetReconnectPolicy(&ReconnectPolicy{InitialInterval: 10 * time.Millisecond, MaxAttempts: 3})
+
+	var mutex sync.Mutex
+	changes := make([]string, 0)
+	if err := p.On("state-changed", func(data interface{}) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		changes = append(changes, fmt.Sprintf("%s -> %s", data.(*StateChange).Old, data.(*StateChange).New))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Connect without reconnect routine
+	p.SetConnecting()
+	assert.Equal(t, StateConnecting, p.State())
+	p.SetConnectFailed(errors.New("board unplugged"))
+	assert.Equal(t, StateDisconnected, p.State())
+	p.SetState(StateConnected, "connected")
+
+	// Each try keep the reconnecting state
+	states := make(chan State, 10)
+	p.StartReconnect(func(ctx context.Context) error {
+		p.SetConnecting()
+		states <- p.State()
+		err := errors.New("board unplugged")
+		p.SetConnectFailed(err)
+		states <- p.State()
+		return err
+	})
+	assert.Eventually(t, func() bool { return p.State() == StateFailed }, time.Second, 10*time.Millisecond)
+	close(states)
+	for state := range states {
+		assert.Equal(t, StateReconnecting, state)
+	}
+
+	expected := []string{
+		"disconnected -> connecting",
+		"connecting -> disconnected",
+		"disconnected -> connected",
+		"connected -> reconnecting",
+		"reconnecting -> failed",
+	}
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(changes) >= len(expected)
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, expected, changes)
+}
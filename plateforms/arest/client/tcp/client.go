@@ -0,0 +1,62 @@
+package tcpClient
+
+import (
+	"net"
+	"time"
+
+	serialClient "github.com/disaster37/gobot-arest/plateforms/arest/client/serial"
+	"go.bug.st/serial"
+)
+
+// DefaultDialTimeout is the timeout to open TCP connection when no timeout is provided
+const DefaultDialTimeout = 10 * time.Second
+
+// Client implement arest interface over raw TCP socket.
+// It use the same line protocol as serial client, like aREST on WiFiServer or serial port behind ser2net.
+type Client struct {
+	address    string
+	serialMode *serial.Mode
+	timeout    time.Duration
+	*serialClient.Client
+}
+
+// NewClient permit to initialize new client Object
+// If serialMode is provided, the serial port settings are negociated with RFC2217 (ser2net with telnet mode)
+func NewClient(address string, serialMode *serial.Mode, timeout time.Duration, isDebug bool) *Client {
+	c := &Client{
+		address:    address,
+		serialMode: serialMode,
+		timeout:    timeout,
+	}
+	c.Client = serialClient.NewClientWithOpener(address, c.open, timeout, isDebug)
+
+	return c
+}
+
+// Address return the TCP address of the board
+func (c *Client) Address() string {
+	return c.address
+}
+
+// open permit to open the TCP connection, and negociate serial settings if needed
+func (c *Client) open() (port serial.Port, err error) {
+	dialTimeout := c.timeout
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+
+	conn, err := net.DialTimeout("tcp", c.address, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpPort := newPort(conn, c.serialMode != nil)
+	if c.serialMode != nil {
+		if err = tcpPort.negotiate(c.serialMode); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	return tcpPort, nil
+}
@@ -0,0 +1,136 @@
+package tcpClient
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+	"go.bug.st/serial"
+)
+
+type ArestTestSuite struct {
+	suite.Suite
+	board  *mockBoard
+	client *Client
+}
+
+func (s *ArestTestSuite) SetupSuite() {
+	// Init logger
+	logrus.SetFormatter(new(prefixed.TextFormatter))
+	logrus.SetLevel(logrus.DebugLevel)
+}
+
+func (s *ArestTestSuite) SetupTest() {
+	s.board = newMockBoard(false)
+	s.client = NewClient(s.board.Address(), nil, 5*time.Second, true)
+}
+
+func (s *ArestTestSuite) TearDownTest() {
+	_ = s.client.Disconnect(context.Background())
+	s.board.Close()
+}
+
+func TestArestTestSuite(t *testing.T) {
+	suite.Run(t, new(ArestTestSuite))
+}
+
+func (s *ArestTestSuite) TestConnect() {
+	err := s.client.Connect(context.Background())
+	assert.NoError(s.T(), err)
+
+	info, err := s.client.Info(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "esp8266", info.Hardware)
+	assert.Equal(s.T(), s.board.Address(), s.client.Address())
+
+	// Board not reachable
+	c := NewClient("127.0.0.1:1", nil, 1*time.Second, false)
+	assert.Error(s.T(), c.Connect(context.Background()))
+}
+
+func (s *ArestTestSuite) TestDigital() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 3, client.ModeOutput))
+	assert.NoError(s.T(), s.client.DigitalWrite(context.Background(), 3, client.LevelHigh))
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 4, client.ModeInput))
+	level, err := s.client.DigitalRead(context.Background(), 4)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), client.LevelHigh, level)
+
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/digital/3/1", "/mode/4/i", "/digital/4"}, s.board.Commands())
+}
+
+func (s *ArestTestSuite) TestReconnect() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.DigitalWrite(context.Background(), 3, client.LevelHigh); err != nil {
+		s.T().Fatal(err)
+	}
+
+	err := s.client.Reconnect(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/digital/3/1", "/", "/mode/3/o", "/digital/3/1"}, s.board.Commands())
+}
+
+func (s *ArestTestSuite) TestConnectionLost() {
+	s.client.SetReconnectPolicy(&client.ReconnectPolicy{InitialInterval: 20 * time.Millisecond, MaxAttempts: 10})
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+
+	isReconnected := make(chan bool, 1)
+	if err := s.client.On("reconnected", func(data interface{}) {
+		isReconnected <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Board close the connection, no command is pending
+	s.board.Drop()
+	select {
+	case <-isReconnected:
+	case <-time.After(10 * time.Second):
+		s.T().Fatal("reconnected event not published")
+	}
+
+	// Pins are restored
+	assert.Equal(s.T(), client.StateConnected, s.client.State())
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/", "/mode/3/o", "/digital/3/0"}, s.board.Commands())
+}
+
+func (s *ArestTestSuite) TestRFC2217() {
+	board := newMockBoard(true)
+	defer board.Close()
+
+	c := NewClient(board.Address(), &serial.Mode{BaudRate: 9600}, 5*time.Second, true)
+	defer c.Disconnect(context.Background())
+
+	err := c.Connect(context.Background())
+	assert.NoError(s.T(), err)
+
+	raw := board.Raw()
+	// Enable COM-PORT-OPTION
+	assert.True(s.T(), bytes.HasPrefix(raw, []byte{iac, will, optionComPort}))
+	// Set baudrate to 9600
+	assert.True(s.T(), bytes.Contains(raw, []byte{iac, sb, optionComPort, comPortSetBaudrate, 0, 0, 0x25, 0x80, iac, se}))
+	// Refuse echo option
+	assert.True(s.T(), bytes.Contains(raw, []byte{iac, dont, 1}))
+	assert.Equal(s.T(), []string{"/"}, board.Commands())
+}
@@ -0,0 +1,135 @@
+package tcpClient
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// mockBoard is a fake aREST board listening on TCP
+type mockBoard struct {
+	listener net.Listener
+	rfc2217  bool
+	commands []string
+	raw      []byte
+	conns    map[net.Conn]struct{}
+	mutex    sync.Mutex
+}
+
+func newMockBoard(rfc2217 bool) *mockBoard {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	m := &mockBoard{
+		listener: listener,
+		rfc2217:  rfc2217,
+		commands: make([]string, 0),
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go m.handle(conn)
+		}
+	}()
+
+	return m
+}
+
+func (m *mockBoard) Address() string {
+	return m.listener.Addr().String()
+}
+
+func (m *mockBoard) Close() {
+	_ = m.listener.Close()
+}
+
+// Drop close the active connections, like when board reboot
+func (m *mockBoard) Drop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for conn := range m.conns {
+		_ = conn.Close()
+	}
+}
+
+func (m *mockBoard) Commands() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string{}, m.commands...)
+}
+
+func (m *mockBoard) Raw() []byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]byte{}, m.raw...)
+}
+
+func (m *mockBoard) handle(conn net.Conn) {
+	m.mutex.Lock()
+	m.conns[conn] = struct{}{}
+	m.mutex.Unlock()
+	defer func() {
+		m.mutex.Lock()
+		delete(m.conns, conn)
+		m.mutex.Unlock()
+		_ = conn.Close()
+	}()
+
+	if m.rfc2217 {
+		// Access server accept COM-PORT-OPTION and ask echo
+		if _, err := conn.Write([]byte{iac, do, optionComPort, iac, will, 1}); err != nil {
+			return
+		}
+	}
+
+	reader := bufio.NewReader(conn)
+	filter := &telnetFilter{}
+	var line strings.Builder
+	buffer := make([]byte, 1024)
+	for {
+		n, err := reader.Read(buffer)
+		if err != nil {
+			return
+		}
+		m.mutex.Lock()
+		m.raw = append(m.raw, buffer[:n]...)
+		m.mutex.Unlock()
+		if m.rfc2217 {
+			n, _ = filter.filter(buffer[:n])
+		}
+
+		for _, c := range buffer[:n] {
+			if c != '\n' {
+				line.WriteByte(c)
+				continue
+			}
+			cmd := strings.TrimSpace(line.String())
+			line.Reset()
+			m.mutex.Lock()
+			m.commands = append(m.commands, cmd)
+			m.mutex.Unlock()
+			if _, err = conn.Write([]byte(m.response(cmd) + "\r\n")); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (m *mockBoard) response(cmd string) string {
+	switch {
+	case cmd == "/":
+		return `{"variables": {"isRebooted": false}, "id": "002", "name": "TFP", "hardware": "esp8266", "connected": true}`
+	case strings.HasPrefix(cmd, "/digital/") && strings.Count(cmd, "/") == 2:
+		return `{"return_value": 1, "id": "002", "name": "TFP", "hardware": "esp8266", "connected": true}`
+	default:
+		return fmt.Sprintf(`{"message": "%s done", "id": "002", "name": "TFP", "hardware": "esp8266", "connected": true}`, cmd)
+	}
+}
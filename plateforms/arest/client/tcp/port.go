@@ -0,0 +1,184 @@
+package tcpClient
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// port implement serial.Port on top of TCP connection
+// Serial settings are only applied when RFC2217 is enabled, else they are managed by the remote side.
+type port struct {
+	conn        net.Conn
+	rfc2217     bool
+	readTimeout time.Duration
+	telnet      *telnetFilter
+	mutexWrite  sync.Mutex
+}
+
+func newPort(conn net.Conn, rfc2217 bool) *port {
+	return &port{
+		conn:    conn,
+		rfc2217: rfc2217,
+		telnet:  &telnetFilter{},
+	}
+}
+
+// SetMode permit to set serial settings on remote port with RFC2217
+func (p *port) SetMode(mode *serial.Mode) error {
+	if !p.rfc2217 {
+		return nil
+	}
+
+	return p.sendComPortOptions(comPortOptions(mode)...)
+}
+
+// Read read data from TCP connection
+// It return 0 without error when read timeout is reached, like serial port
+func (p *port) Read(b []byte) (n int, err error) {
+	for {
+		if p.readTimeout > 0 {
+			if err = p.conn.SetReadDeadline(time.Now().Add(p.readTimeout)); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err = p.conn.Read(b)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return 0, nil
+			}
+			return 0, err
+		}
+
+		if !p.rfc2217 {
+			return n, nil
+		}
+
+		// Remove telnet commands and answer to negotiation
+		var replies []byte
+		n, replies = p.telnet.filter(b[:n])
+		if len(replies) > 0 {
+			if err = p.writeRaw(replies); err != nil {
+				return 0, err
+			}
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// Write write data on TCP connection
+func (p *port) Write(b []byte) (n int, err error) {
+	if !p.rfc2217 {
+		p.mutexWrite.Lock()
+		defer p.mutexWrite.Unlock()
+		return p.conn.Write(b)
+	}
+
+	if err = p.writeRaw(escapeIAC(b)); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// ResetInputBuffer purge the remote receive buffer with RFC2217
+func (p *port) ResetInputBuffer() error {
+	if !p.rfc2217 {
+		return nil
+	}
+	return p.sendComPortOptions(comPortOption{cmd: comPortPurgeData, value: []byte{purgeReceive}})
+}
+
+// ResetOutputBuffer purge the remote transmit buffer with RFC2217
+func (p *port) ResetOutputBuffer() error {
+	if !p.rfc2217 {
+		return nil
+	}
+	return p.sendComPortOptions(comPortOption{cmd: comPortPurgeData, value: []byte{purgeTransmit}})
+}
+
+// SetDTR set DTR line on remote port with RFC2217
+func (p *port) SetDTR(dtr bool) error {
+	if !p.rfc2217 {
+		return nil
+	}
+	value := controlDTROff
+	if dtr {
+		value = controlDTROn
+	}
+	return p.sendComPortOptions(comPortOption{cmd: comPortSetControl, value: []byte{value}})
+}
+
+// SetRTS set RTS line on remote port with RFC2217
+func (p *port) SetRTS(rts bool) error {
+	if !p.rfc2217 {
+		return nil
+	}
+	value := controlRTSOff
+	if rts {
+		value = controlRTSOn
+	}
+	return p.sendComPortOptions(comPortOption{cmd: comPortSetControl, value: []byte{value}})
+}
+
+// GetModemStatusBits is not supported on TCP, it always return empty status
+func (p *port) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{}, nil
+}
+
+// Close close the TCP connection
+func (p *port) Close() error {
+	return p.conn.Close()
+}
+
+// Break send break on remote port with RFC2217
+func (p *port) Break(t time.Duration) error {
+	if !p.rfc2217 {
+		return nil
+	}
+	if err := p.sendComPortOptions(comPortOption{cmd: comPortSetControl, value: []byte{controlBreakOn}}); err != nil {
+		return err
+	}
+	time.Sleep(t)
+	return p.sendComPortOptions(comPortOption{cmd: comPortSetControl, value: []byte{controlBreakOff}})
+}
+
+// SetReadTimeout set the timeout of each read
+func (p *port) SetReadTimeout(t time.Duration) error {
+	p.readTimeout = t
+	return nil
+}
+
+// negotiate permit to enable COM-PORT-OPTION and set serial settings
+func (p *port) negotiate(mode *serial.Mode) error {
+	if err := p.writeRaw([]byte{iac, will, optionComPort}); err != nil {
+		return err
+	}
+
+	return p.SetMode(mode)
+}
+
+func (p *port) sendComPortOptions(options ...comPortOption) error {
+	for _, option := range options {
+		if err := p.writeRaw(option.encode()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *port) writeRaw(b []byte) (err error) {
+	p.mutexWrite.Lock()
+	defer p.mutexWrite.Unlock()
+
+	_, err = p.conn.Write(b)
+	return err
+}
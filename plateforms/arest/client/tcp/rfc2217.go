@@ -0,0 +1,190 @@
+package tcpClient
+
+import (
+	"encoding/binary"
+
+	"go.bug.st/serial"
+)
+
+// Telnet commands
+const (
+	se   byte = 240
+	sb   byte = 250
+	will byte = 251
+	wont byte = 252
+	do   byte = 253
+	dont byte = 254
+	iac  byte = 255
+)
+
+// RFC2217 COM-PORT-OPTION and its commands, sent from client to access server
+const (
+	optionComPort byte = 44
+
+	comPortSetBaudrate byte = 1
+	comPortSetDataSize byte = 2
+	comPortSetParity   byte = 3
+	comPortSetStopSize byte = 4
+	comPortSetControl  byte = 5
+	comPortPurgeData   byte = 12
+)
+
+// RFC2217 values
+const (
+	parityNone  byte = 1
+	parityOdd   byte = 2
+	parityEven  byte = 3
+	parityMark  byte = 4
+	paritySpace byte = 5
+
+	stopSizeOne          byte = 1
+	stopSizeTwo          byte = 2
+	stopSizeOnePointFive byte = 3
+
+	controlBreakOn  byte = 5
+	controlBreakOff byte = 6
+	controlDTROn    byte = 8
+	controlDTROff   byte = 9
+	controlRTSOn    byte = 11
+	controlRTSOff   byte = 12
+
+	purgeReceive  byte = 1
+	purgeTransmit byte = 2
+)
+
+// comPortOption is a COM-PORT-OPTION sub-negotiation
+type comPortOption struct {
+	cmd   byte
+	value []byte
+}
+
+// encode return the telnet sub-negotiation: IAC SB COM-PORT-OPTION <cmd> <value> IAC SE
+func (o comPortOption) encode() []byte {
+	b := []byte{iac, sb, optionComPort, o.cmd}
+	b = append(b, escapeIAC(o.value)...)
+	return append(b, iac, se)
+}
+
+// comPortOptions return the sub-negotiations to set the serial mode
+// Zero values are not sent, so the access server keep its current settings
+func comPortOptions(mode *serial.Mode) []comPortOption {
+	options := make([]comPortOption, 0, 4)
+
+	if mode.BaudRate > 0 {
+		value := make([]byte, 4)
+		binary.BigEndian.PutUint32(value, uint32(mode.BaudRate))
+		options = append(options, comPortOption{cmd: comPortSetBaudrate, value: value})
+	}
+
+	if mode.DataBits > 0 {
+		options = append(options, comPortOption{cmd: comPortSetDataSize, value: []byte{byte(mode.DataBits)}})
+	}
+
+	parity := parityNone
+	switch mode.Parity {
+	case serial.OddParity:
+		parity = parityOdd
+	case serial.EvenParity:
+		parity = parityEven
+	case serial.MarkParity:
+		parity = parityMark
+	case serial.SpaceParity:
+		parity = paritySpace
+	}
+	options = append(options, comPortOption{cmd: comPortSetParity, value: []byte{parity}})
+
+	stopSize := stopSizeOne
+	switch mode.StopBits {
+	case serial.OnePointFiveStopBits:
+		stopSize = stopSizeOnePointFive
+	case serial.TwoStopBits:
+		stopSize = stopSizeTwo
+	}
+	options = append(options, comPortOption{cmd: comPortSetStopSize, value: []byte{stopSize}})
+
+	return options
+}
+
+// escapeIAC double IAC byte on data
+func escapeIAC(b []byte) []byte {
+	escaped := make([]byte, 0, len(b))
+	for _, c := range b {
+		escaped = append(escaped, c)
+		if c == iac {
+			escaped = append(escaped, iac)
+		}
+	}
+
+	return escaped
+}
+
+type telnetState int
+
+const (
+	stateData telnetState = iota
+	stateIAC
+	stateOption
+	stateSub
+	stateSubIAC
+)
+
+// telnetFilter remove telnet commands from data stream
+// It keep its state between reads because of commands can be split on several reads
+type telnetFilter struct {
+	state   telnetState
+	command byte
+}
+
+// filter remove telnet commands from b in place.
+// It return the data length, and the replies to send to refuse unsupported options.
+func (f *telnetFilter) filter(b []byte) (n int, replies []byte) {
+	for _, c := range b {
+		switch f.state {
+		case stateData:
+			if c == iac {
+				f.state = stateIAC
+				continue
+			}
+			b[n] = c
+			n++
+		case stateIAC:
+			switch c {
+			case iac:
+				// Escaped 0xFF data
+				b[n] = c
+				n++
+				f.state = stateData
+			case will, wont, do, dont:
+				f.command = c
+				f.state = stateOption
+			case sb:
+				f.state = stateSub
+			default:
+				f.state = stateData
+			}
+		case stateOption:
+			if c != optionComPort {
+				switch f.command {
+				case do:
+					replies = append(replies, iac, wont, c)
+				case will:
+					replies = append(replies, iac, dont, c)
+				}
+			}
+			f.state = stateData
+		case stateSub:
+			// Ignore access server notifications
+			if c == iac {
+				f.state = stateSubIAC
+			}
+		case stateSubIAC:
+			if c == se {
+				f.state = stateData
+			} else {
+				f.state = stateSub
+			}
+		}
+	}
+
+	return n, replies
+}
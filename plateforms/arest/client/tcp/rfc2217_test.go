@@ -0,0 +1,40 @@
+package tcpClient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.bug.st/serial"
+)
+
+func TestComPortOptions(t *testing.T) {
+	options := comPortOptions(&serial.Mode{BaudRate: 115200, DataBits: 8, Parity: serial.EvenParity, StopBits: serial.TwoStopBits})
+	assert.Equal(t, []comPortOption{
+		{cmd: comPortSetBaudrate, value: []byte{0, 0x01, 0xC2, 0x00}},
+		{cmd: comPortSetDataSize, value: []byte{8}},
+		{cmd: comPortSetParity, value: []byte{parityEven}},
+		{cmd: comPortSetStopSize, value: []byte{stopSizeTwo}},
+	}, options)
+
+	// Value with IAC is escaped
+	option := comPortOption{cmd: comPortSetBaudrate, value: []byte{0, 0, 0, 0xFF}}
+	assert.Equal(t, []byte{iac, sb, optionComPort, comPortSetBaudrate, 0, 0, 0, 0xFF, 0xFF, iac, se}, option.encode())
+}
+
+func TestTelnetFilter(t *testing.T) {
+	f := &telnetFilter{}
+
+	// Data with commands and escaped IAC
+	b := []byte{'{', iac, do, optionComPort, '}', iac, iac, iac, will, 1}
+	n, replies := f.filter(b)
+	assert.Equal(t, []byte{'{', '}', 0xFF}, b[:n])
+	assert.Equal(t, []byte{iac, dont, 1}, replies)
+
+	// Sub-negotiation split on several reads
+	b = []byte{'a', iac, sb, optionComPort, 101}
+	n, _ = f.filter(b)
+	assert.Equal(t, []byte{'a'}, b[:n])
+	b = []byte{0, 0, iac, se, 'b'}
+	n, _ = f.filter(b)
+	assert.Equal(t, []byte{'b'}, b[:n])
+}
@@ -0,0 +1,325 @@
+package websocketClient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultTimeout is the timeout to wait the gateway response when no timeout is provided
+const DefaultTimeout = 10 * time.Second
+
+// Request is the message sent to the gateway.
+// The command is the aREST command, like /digital/3/1
+type Request struct {
+	ID      uint64 `json:"id"`
+	Command string `json:"command"`
+}
+
+// Response is the message received from the gateway.
+// The body is the aREST JSON response of the command sent with the same ID.
+type Response struct {
+	ID   uint64          `json:"id"`
+	Body json.RawMessage `json:"body"`
+}
+
+// Client implement arest interface over one persistent WebSocket connection.
+// Each command is sent as Request and the gateway answer with Response with the same ID,
+// so concurrent commands can be sent on the same connection.
+type Client struct {
+	url     string
+	header  http.Header
+	dialer  *websocket.Dialer
+	conn    *websocket.Conn
+	timeout time.Duration
+	isDebug bool
+
+	// It permit to protect connection when connect / disconnect
+	mutex sync.Mutex
+	// It permit to have only one writer on connection
+	writeMutex sync.Mutex
+
+	// It permit to route responses to the pending commands
+	pendingMutex sync.Mutex
+	pending      map[uint64]chan []byte
+	lastID       uint64
+
+	*client.Protocol
+}
+
+// NewClient permit to initialize new client Object
+// The url is the gateway URL, like ws://localhost:8080/arest. The header is sent on handshake, it can be nil.
+func NewClient(url string, header http.Header, timeout time.Duration, isDebug bool) *Client {
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	clientArest := &Client{
+		url:     url,
+		header:  header,
+		dialer:  &websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: timeout},
+		timeout: timeout,
+		isDebug: isDebug,
+		pending: map[uint64]chan []byte{},
+	}
+	clientArest.Protocol = client.NewProtocol(clientArest, isDebug)
+
+	return clientArest
+}
+
+// Client permit to get curent WebSocket connection
+func (c *Client) Client() *websocket.Conn {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.conn
+}
+
+// Dialer permit to get the dialer used to open connection, to set TLS config for exemple
+func (c *Client) Dialer() *websocket.Dialer {
+	return c.dialer
+}
+
+// URL return the gateway URL
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Send permit to send command to the board throught WebSocket
+// It implement client.Transport interface
+func (c *Client) Send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	if !c.IsConnected() {
+		return nil, client.ErrNotConnected
+	}
+
+	return c.send(ctx, cmd)
+}
+
+// send write the request and wait the response with the same ID
+func (c *Client) send(ctx context.Context, cmd *client.Command) (body []byte, err error) {
+	c.mutex.Lock()
+	conn := c.conn
+	c.mutex.Unlock()
+	if conn == nil {
+		return nil, client.ErrNotConnected
+	}
+
+	req := &Request{
+		ID:      atomic.AddUint64(&c.lastID, 1),
+		Command: cmd.String(),
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(chan []byte, 1)
+	c.pendingMutex.Lock()
+	c.pending[req.ID] = res
+	c.pendingMutex.Unlock()
+	defer func() {
+		c.pendingMutex.Lock()
+		delete(c.pending, req.ID)
+		c.pendingMutex.Unlock()
+	}()
+
+	c.writeMutex.Lock()
+	_ = conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	err = conn.WriteMessage(websocket.TextMessage, data)
+	c.writeMutex.Unlock()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when send %s", cmd)
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case body, ok := <-res:
+		if !ok {
+			return nil, errors.Wrapf(client.ErrNotConnected, "Connection lost when wait response of %s", cmd)
+		}
+		return body, nil
+	case <-timer.C:
+		c.Publish("timeout", true)
+		return nil, errors.Wrapf(client.ErrTimeout, "No response of %s", cmd)
+	}
+}
+
+// readProcess read responses from connection and route them to pending commands
+// It run until the connection is closed
+func (c *Client) readProcess(conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if c.isDebug {
+				log.Debugf("Stop read WebSocket: %s", err.Error())
+			}
+			c.handleLost(conn)
+			return
+		}
+
+		if c.isDebug {
+			log.Debugf("Message: %s", string(data))
+		}
+
+		resp := &Response{}
+		if err = json.Unmarshal(data, resp); err != nil {
+			log.Errorf("Bad message from gateway: %s", string(data))
+			continue
+		}
+
+		c.pendingMutex.Lock()
+		res, ok := c.pending[resp.ID]
+		if ok {
+			delete(c.pending, resp.ID)
+			res <- resp.Body
+		}
+		c.pendingMutex.Unlock()
+
+		if !ok && c.isDebug {
+			log.Debugf("Drop response %d without pending command", resp.ID)
+		}
+	}
+}
+
+// failPending permit to release all pending commands
+func (c *Client) failPending() {
+	c.pendingMutex.Lock()
+	defer c.pendingMutex.Unlock()
+
+	for id, res := range c.pending {
+		close(res)
+		delete(c.pending, id)
+	}
+}
+
+// handleLost is called when the connection is closed by other side
+// It try to reconnect with the reconnect policy
+func (c *Client) handleLost(conn *websocket.Conn) {
+	c.mutex.Lock()
+	if c.conn != conn {
+		// Closed by Disconnect
+		c.mutex.Unlock()
+		return
+	}
+	_ = conn.Close()
+	c.conn = nil
+	wasConnected := c.IsConnected()
+	c.mutex.Unlock()
+
+	c.failPending()
+
+	if !wasConnected {
+		return
+	}
+	c.SetState(client.StateDisconnected, "connection lost")
+	c.Publish("disconnected", true)
+	c.StartReconnect(c.reconnect)
+}
+
+// Connect start connection to the gateway
+// It call the root url to check if board is online
+func (c *Client) Connect(ctx context.Context) (err error) {
+
+	if c.IsConnected() {
+		return
+	}
+
+	c.SetConnecting()
+	defer func() {
+		if err != nil {
+			c.SetConnectFailed(err)
+		}
+	}()
+
+	conn, _, err := c.dialer.DialContext(ctx, c.url, c.header)
+	if err != nil {
+		return errors.Wrapf(err, "Can't connect on %s", c.url)
+	}
+	c.mutex.Lock()
+	c.conn = conn
+	c.mutex.Unlock()
+	go c.readProcess(conn)
+
+	// Try connexion
+	// The root url is used because of it return identity and variables
+	body, err := c.send(ctx, client.RootCommand())
+	if err == nil {
+		_, err = c.HandleInfo(client.RootCommand(), body)
+	}
+	if err != nil {
+		c.mutex.Lock()
+		c.conn = nil
+		c.mutex.Unlock()
+		_ = conn.Close()
+		return err
+	}
+
+	c.Publish("connected", true)
+	c.SetState(client.StateConnected, "connected")
+
+	return nil
+}
+
+// Disconnect close connecion to the gateway
+// It stop the reconnect routine if any
+func (c *Client) Disconnect(ctx context.Context) (err error) {
+	c.StopReconnect()
+
+	c.mutex.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.mutex.Unlock()
+	c.SetState(client.StateDisconnected, "disconnect")
+
+	if conn != nil {
+		c.writeMutex.Lock()
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		c.writeMutex.Unlock()
+		if err = conn.Close(); err != nil {
+			return err
+		}
+	}
+	c.failPending()
+
+	c.Publish("disconnected", true)
+
+	return nil
+}
+
+// Reconnect close and start connection to the gateway
+func (c *Client) Reconnect(ctx context.Context) (err error) {
+	err = c.Disconnect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.reconnect(ctx)
+}
+
+// reconnect start connection and restore pins
+func (c *Client) reconnect(ctx context.Context) (err error) {
+	err = c.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Set pin mode and output
+	if err = c.RestorePins(ctx); err != nil {
+		return err
+	}
+
+	c.Publish("reconnected", true)
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package websocketClient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+)
+
+type ArestTestSuite struct {
+	suite.Suite
+	gateway *mockGateway
+	client  *Client
+}
+
+func (s *ArestTestSuite) SetupSuite() {
+	// Init logger
+	logrus.SetFormatter(new(prefixed.TextFormatter))
+	logrus.SetLevel(logrus.DebugLevel)
+
+}
+
+func (s *ArestTestSuite) SetupTest() {
+	s.gateway = newMockGateway()
+	s.client = NewClient(s.gateway.URL(), http.Header{"X-Api-Key": []string{"secret"}}, 1*time.Second, true)
+	s.client.SetReconnectPolicy(&client.ReconnectPolicy{InitialInterval: 10 * time.Millisecond, MaxAttempts: 3})
+}
+
+func (s *ArestTestSuite) TearDownTest() {
+	_ = s.client.Disconnect(context.Background())
+	s.gateway.Close()
+}
+
+func TestArestTestSuite(t *testing.T) {
+	suite.Run(t, new(ArestTestSuite))
+}
+
+func (s *ArestTestSuite) TestConnect() {
+	err := s.client.Connect(context.Background())
+	assert.NoError(s.T(), err)
+
+	info, err := s.client.Info(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "esp32", info.Hardware)
+	assert.Equal(s.T(), "secret", s.gateway.Header().Get("X-Api-Key"))
+
+	// Gateway not reachable
+	c := NewClient("ws://127.0.0.1:1", nil, 1*time.Second, false)
+	assert.Error(s.T(), c.Connect(context.Background()))
+}
+
+func (s *ArestTestSuite) TestDigital() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 3, client.ModeOutput))
+	assert.NoError(s.T(), s.client.DigitalWrite(context.Background(), 3, client.LevelHigh))
+
+	assert.NoError(s.T(), s.client.SetPinMode(context.Background(), 4, client.ModeInput))
+	level, err := s.client.DigitalRead(context.Background(), 4)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), client.LevelHigh, level)
+
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/digital/3/1", "/mode/4/i", "/digital/4"}, s.gateway.Commands())
+}
+
+func (s *ArestTestSuite) TestConcurrentCommands() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		value, err := s.client.ReadValue(context.Background(), "slow")
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), json.Number("1"), value)
+	}()
+	go func() {
+		defer wg.Done()
+		// Let slow command be sent before
+		time.Sleep(50 * time.Millisecond)
+		start := time.Now()
+		value, err := s.client.ReadValue(context.Background(), "fast")
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), json.Number("2"), value)
+		// Not blocked by slow command
+		assert.Less(s.T(), time.Since(start), 150*time.Millisecond)
+	}()
+	wg.Wait()
+}
+
+func (s *ArestTestSuite) TestTimeout() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+
+	isTimeout := make(chan bool, 1)
+	if err := s.client.On("timeout", func(data interface{}) {
+		isTimeout <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	_, err := s.client.ReadValue(context.Background(), "noanswer")
+	assert.ErrorIs(s.T(), err, client.ErrTimeout)
+
+	select {
+	case <-isTimeout:
+	case <-time.After(time.Second):
+		s.T().Error("timeout event not published")
+	}
+}
+
+func (s *ArestTestSuite) TestConnectionLost() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+
+	isDisconnected := make(chan bool, 1)
+	isReconnected := make(chan bool, 1)
+	if err := s.client.On("disconnected", func(data interface{}) {
+		isDisconnected <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.On("reconnected", func(data interface{}) {
+		isReconnected <- true
+	}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	s.gateway.Drop()
+
+	select {
+	case <-isDisconnected:
+	case <-time.After(time.Second):
+		s.T().Fatal("disconnected event not published")
+	}
+	select {
+	case <-isReconnected:
+	case <-time.After(2 * time.Second):
+		s.T().Fatal("reconnected event not published")
+	}
+
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/", "/mode/3/o", "/digital/3/0"}, s.gateway.Commands())
+}
+
+func (s *ArestTestSuite) TestReconnect() {
+	if err := s.client.Connect(context.Background()); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.SetPinMode(context.Background(), 3, client.ModeOutput); err != nil {
+		s.T().Fatal(err)
+	}
+	if err := s.client.DigitalWrite(context.Background(), 3, client.LevelHigh); err != nil {
+		s.T().Fatal(err)
+	}
+
+	err := s.client.Reconnect(context.Background())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"/", "/mode/3/o", "/digital/3/1", "/", "/mode/3/o", "/digital/3/1"}, s.gateway.Commands())
+}
@@ -0,0 +1,120 @@
+package websocketClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// mockGateway is a fake aREST WebSocket gateway
+type mockGateway struct {
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+	conns    []*websocket.Conn
+	commands []string
+	header   http.Header
+	mutex    sync.Mutex
+}
+
+func newMockGateway() *mockGateway {
+	m := &mockGateway{
+		commands: make([]string, 0),
+		conns:    make([]*websocket.Conn, 0),
+	}
+	m.server = httptest.NewServer(http.HandlerFunc(m.handle))
+
+	return m
+}
+
+func (m *mockGateway) URL() string {
+	return "ws" + strings.TrimPrefix(m.server.URL, "http")
+}
+
+func (m *mockGateway) Close() {
+	m.server.CloseClientConnections()
+	m.server.Close()
+}
+
+func (m *mockGateway) Commands() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string{}, m.commands...)
+}
+
+func (m *mockGateway) Header() http.Header {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.header
+}
+
+// Drop close all connections from gateway side
+func (m *mockGateway) Drop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, conn := range m.conns {
+		_ = conn.Close()
+	}
+	m.conns = make([]*websocket.Conn, 0)
+}
+
+func (m *mockGateway) handle(w http.ResponseWriter, r *http.Request) {
+	conn, err := m.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	m.mutex.Lock()
+	m.header = r.Header
+	m.conns = append(m.conns, conn)
+	m.mutex.Unlock()
+
+	writeMutex := sync.Mutex{}
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		req := &Request{}
+		if err = json.Unmarshal(data, req); err != nil {
+			return
+		}
+		m.mutex.Lock()
+		m.commands = append(m.commands, req.Command)
+		m.mutex.Unlock()
+
+		// Answer each request on its own routine so responses can be out of order
+		go func(req *Request) {
+			body, delay := m.response(req.Command)
+			if body == "" {
+				return
+			}
+			time.Sleep(delay)
+			data, _ := json.Marshal(&Response{ID: req.ID, Body: json.RawMessage(body)})
+			writeMutex.Lock()
+			defer writeMutex.Unlock()
+			_ = conn.WriteMessage(websocket.TextMessage, data)
+		}(req)
+	}
+}
+
+func (m *mockGateway) response(cmd string) (body string, delay time.Duration) {
+	switch {
+	case cmd == "/":
+		return `{"variables": {"isRebooted": false}, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`, 0
+	case cmd == "/noanswer":
+		return "", 0
+	case cmd == "/slow":
+		return `{"slow": 1, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`, 200 * time.Millisecond
+	case cmd == "/fast":
+		return `{"fast": 2, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`, 0
+	case strings.HasPrefix(cmd, "/digital/") && strings.Count(cmd, "/") == 2:
+		return `{"return_value": 1, "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`, 0
+	default:
+		return fmt.Sprintf(`{"message": "%s done", "id": "002", "name": "TFP", "hardware": "esp32", "connected": true}`, cmd), 0
+	}
+}
@@ -0,0 +1,102 @@
+package arest
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	coapClient "github.com/disaster37/gobot-arest/plateforms/arest/client/coap"
+	"github.com/pkg/errors"
+	"gobot.io/x/gobot"
+)
+
+// DefaultCoAPPort is the CoAP port used when URL not provide it
+const DefaultCoAPPort = "5683"
+
+// CoAPAdaptor is the Gobot Adaptor for Arest based boards reachable throught CoAP
+type CoAPAdaptor struct {
+	Adaptor
+}
+
+func init() {
+	if err := RegisterTransport("coap", newCoAPBoard); err != nil {
+		panic(err)
+	}
+}
+
+// newCoAPBoard create the CoAP board from URL like coap://host:5683?timeout=5s&ack_timeout=2s&max_retransmit=4
+func newCoAPBoard(u *url.URL) (board Board, err error) {
+	if u.Hostname() == "" {
+		return nil, errors.Errorf("No address found on URL %s", u.String())
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), DefaultCoAPPort)
+	}
+
+	opts, err := ParseURLOptions(u)
+	if err != nil {
+		return nil, err
+	}
+
+	ackTimeout := coapClient.DefaultAckTimeout
+	if rawAckTimeout := u.Query().Get("ack_timeout"); rawAckTimeout != "" {
+		if ackTimeout, err = time.ParseDuration(rawAckTimeout); err != nil {
+			return nil, errors.Wrapf(err, "Bad ack_timeout %s", rawAckTimeout)
+		}
+	}
+	maxRetransmit := coapClient.DefaultMaxRetransmit
+	if rawMaxRetransmit := u.Query().Get("max_retransmit"); rawMaxRetransmit != "" {
+		if maxRetransmit, err = strconv.Atoi(rawMaxRetransmit); err != nil {
+			return nil, errors.Wrapf(err, "Bad max_retransmit %s", rawMaxRetransmit)
+		}
+	}
+
+	c := coapClient.NewClient(address, opts.Timeout, opts.IsDebug)
+	c.SetRetransmission(ackTimeout, maxRetransmit)
+
+	return c, nil
+}
+
+// NewCoAPAdaptor returns a new CoAP Arest Adaptor which optionally accepts:
+//
+//	string: The board name
+//	time.Duration: The timeout to wait board response, including retransmissions. By default it wait all retransmissions
+//	bool: The debug mode
+//	client.BoardInfo: The expected board identity, checked on each connect
+//	client.ReconnectPolicy: The policy used to reconnect when connection is lost
+func NewCoAPAdaptor(address string, args ...interface{}) *Adaptor {
+	a := &Adaptor{
+		name:    gobot.DefaultName("CoAPArest"),
+		isDebug: false,
+		timeout: 0,
+		Eventer: gobot.NewEventer(),
+	}
+
+	var expected *client.BoardInfo
+	var policy *client.ReconnectPolicy
+
+	for _, arg := range args {
+		switch argTmp := arg.(type) {
+		case string:
+			a.name = argTmp
+		case time.Duration:
+			a.timeout = argTmp
+		case bool:
+			a.isDebug = argTmp
+		case client.BoardInfo:
+			expected = &argTmp
+		case client.ReconnectPolicy:
+			policy = &argTmp
+		}
+	}
+
+	board := coapClient.NewClient(address, a.timeout, a.isDebug)
+	board.SetExpectedInfo(expected)
+	board.SetReconnectPolicy(policy)
+	a.setBoard(board)
+
+	return a
+}
@@ -0,0 +1,54 @@
+package arest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	coapClient "github.com/disaster37/gobot-arest/plateforms/arest/client/coap"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*CoAPAdaptor)(nil)
+var _ Board = (*coapClient.Client)(nil)
+
+func TestArestCoAPAdaptor(t *testing.T) {
+
+	// With basic parameters
+	a := NewCoAPAdaptor("localhost:5683")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "CoAPArest"), true)
+	gobottest.Assert(t, a.Board.(*coapClient.Client).Timeout(), coapClient.DefaultTimeout)
+
+	// With all parameters
+	a = NewCoAPAdaptor("localhost:5683", 5*time.Second, "TEST", true, client.BoardInfo{ID: "002"})
+	gobottest.Assert(t, "TEST", a.Name())
+	gobottest.Assert(t, 5*time.Second, a.timeout)
+	gobottest.Assert(t, true, a.isDebug)
+	gobottest.Assert(t, "002", a.Board.(*coapClient.Client).ExpectedInfo().ID)
+}
+
+func TestNewCoAPBoard(t *testing.T) {
+	a, err := NewAdaptorFromURL("coap://192.168.1.10?ack_timeout=1s&max_retransmit=2&name=node")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Name(), "node")
+	board, ok := a.Board.(*coapClient.Client)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, board.Address(), "192.168.1.10:5683")
+	// Timeout wait all retransmissions: 1s * (2^3 - 1) * 1.5
+	gobottest.Assert(t, board.Timeout(), 10500*time.Millisecond)
+
+	// IPv6
+	a, err = NewAdaptorFromURL("coap://[fe80::1]")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Board.(*coapClient.Client).Address(), "[fe80::1]:5683")
+
+	// Bad URL
+	_, err = NewAdaptorFromURL("coap://192.168.1.10?ack_timeout=bad")
+	gobottest.Refute(t, err, nil)
+	_, err = NewAdaptorFromURL("coap://192.168.1.10?max_retransmit=bad")
+	gobottest.Refute(t, err, nil)
+	_, err = NewAdaptorFromURL("coap:///node")
+	gobottest.Refute(t, err, nil)
+}
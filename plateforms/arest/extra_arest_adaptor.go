@@ -2,6 +2,10 @@ package arest
 
 import (
 	"context"
+	"time"
+
+	"github.com/disaster37/gobot-arest/drivers/extra"
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 )
 
 // ValueRead can read on value on plateform like aRest
@@ -23,3 +27,41 @@ func (a *Adaptor) FunctionCall(name string, parameters string) (val int, err err
 	ctx := context.TODO()
 	return a.Board.CallFunction(ctx, name, parameters)
 }
+
+// FunctionCallWithResult can call function on plateform like aRest and return the full response
+func (a *Adaptor) FunctionCallWithResult(name string, parameters string) (res *client.CallFunctionResult, err error) {
+	ctx := context.TODO()
+	return a.Board.CallFunctionWithResult(ctx, name, parameters)
+}
+
+// ValuesReadInto read all values and fill the struct pointed by out from `arest` tags.
+// See extra.ValuesDecoder to use custom converters.
+func (a *Adaptor) ValuesReadInto(out interface{}) (err error) {
+	return extra.ReadValuesInto(a, out)
+}
+
+// ReadInt read the variable as int
+func (a *Adaptor) ReadInt(name string) (val int, err error) {
+	return extra.ReadInt(a, name)
+}
+
+// ReadFloat read the variable as float64
+func (a *Adaptor) ReadFloat(name string) (val float64, err error) {
+	return extra.ReadFloat(a, name)
+}
+
+// ReadBool read the variable as bool
+func (a *Adaptor) ReadBool(name string) (val bool, err error) {
+	return extra.ReadBool(a, name)
+}
+
+// ReadString read the variable as string
+func (a *Adaptor) ReadString(name string) (val string, err error) {
+	return extra.ReadString(a, name)
+}
+
+// ReadDuration read the variable as time.Duration
+// Numeric value is multiplied by unit, string value is parsed with time.ParseDuration
+func (a *Adaptor) ReadDuration(name string, unit time.Duration) (val time.Duration, err error) {
+	return extra.ReadDuration(a, name, unit)
+}
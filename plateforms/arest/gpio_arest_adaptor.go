@@ -46,3 +46,28 @@ func (a *Adaptor) DigitalRead(pin string) (val int, err error) {
 
 	return a.Board.DigitalRead(ctx, p)
 }
+
+// PwmWrite writes the 0-255 value to the specified pin
+func (a *Adaptor) PwmWrite(pin string, level byte) (err error) {
+
+	p, err := strconv.Atoi(pin)
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+
+	if a.Board.Pins()[p] == nil {
+		err = a.Board.SetPinMode(ctx, p, client.ModeOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	return a.Board.AnalogWrite(ctx, p, int(level))
+}
+
+// ServoWrite writes the 0-180 degree angle to the specified pin.
+// The angle is sent as is on /analog endpoint, so the board firmware need to drive the servo from it.
+func (a *Adaptor) ServoWrite(pin string, angle byte) (err error) {
+	return a.PwmWrite(pin, angle)
+}
@@ -31,12 +31,26 @@ func (m mockArestBoard) Disconnect(ctx context.Context) error {
 	return m.disconnectError
 }
 func (mockArestBoard) Reconnect(ctx context.Context) error { return nil }
+func (mockArestBoard) State() client.State                 { return client.StateConnected }
+func (mockArestBoard) Info(ctx context.Context) (info *client.BoardInfo, err error) {
+	return &client.BoardInfo{
+		ID:        "002",
+		Name:      "TFP",
+		Hardware:  "arduino",
+		Connected: true,
+		Variables: []string{"test"},
+	}, nil
+}
 func (m mockArestBoard) Pins() map[int]*client.Pin {
 	return m.pins
 }
 func (mockArestBoard) SetPinMode(ctx context.Context, pin int, mode string) (err error) { return }
 func (mockArestBoard) DigitalRead(ctx context.Context, pin int) (level int, err error)  { return }
 func (mockArestBoard) DigitalWrite(ctx context.Context, pin int, level int) (err error) { return nil }
+func (mockArestBoard) AnalogWrite(ctx context.Context, pin int, value int) (err error)  { return nil }
+func (mockArestBoard) AnalogRead(ctx context.Context, pin int) (value int, err error) {
+	return 512, nil
+}
 func (mockArestBoard) ReadValue(ctx context.Context, name string) (value interface{}, err error) {
 	return 10, nil
 }
@@ -48,6 +62,16 @@ func (mockArestBoard) ReadValues(ctx context.Context) (values map[string]interfa
 func (mockArestBoard) CallFunction(ctx context.Context, name string, param string) (resp int, err error) {
 	return
 }
+func (mockArestBoard) CallFunctionWithResult(ctx context.Context, name string, param string) (res *client.CallFunctionResult, err error) {
+	return &client.CallFunctionResult{
+		ReturnValue: 0,
+		Message:     "Function test called",
+		ID:          "002",
+		Name:        "TFP",
+		Hardware:    "arduino",
+		Connected:   true,
+	}, nil
+}
 func (m mockArestBoard) AddPin(name int, pin *client.Pin) {
 	m.pins[name] = pin
 }
@@ -0,0 +1,71 @@
+package arest
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// TransportFactory create a Board from the connection URL
+type TransportFactory func(u *url.URL) (board Board, err error)
+
+var (
+	transports      = make(map[string]TransportFactory)
+	mutexTransports sync.RWMutex
+)
+
+// RegisterTransport permit to add new transport for the given URL scheme.
+// It's usually called on init function of the package that implement the transport.
+func RegisterTransport(scheme string, factory TransportFactory) (err error) {
+	scheme = strings.ToLower(scheme)
+	if scheme == "" {
+		return errors.New("Transport scheme can't be empty")
+	}
+	if factory == nil {
+		return errors.Errorf("Transport factory for scheme %s can't be nil", scheme)
+	}
+
+	mutexTransports.Lock()
+	defer mutexTransports.Unlock()
+
+	if _, ok := transports[scheme]; ok {
+		return errors.Errorf("Transport for scheme %s is already registered", scheme)
+	}
+	transports[scheme] = factory
+
+	return nil
+}
+
+// Transports return the sorted list of registered transport schemes
+func Transports() []string {
+	mutexTransports.RLock()
+	defer mutexTransports.RUnlock()
+
+	schemes := make([]string, 0, len(transports))
+	for scheme := range transports {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	return schemes
+}
+
+// NewBoard create a Board from connection URL with the transport registered for its scheme
+func NewBoard(rawURL string) (board Board, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Can't parse board URL %s", rawURL)
+	}
+
+	mutexTransports.RLock()
+	factory, ok := transports[strings.ToLower(u.Scheme)]
+	mutexTransports.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("No transport registered for scheme %s", u.Scheme)
+	}
+
+	return factory(u)
+}
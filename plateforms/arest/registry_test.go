@@ -0,0 +1,63 @@
+package arest
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	restClient "github.com/disaster37/gobot-arest/plateforms/arest/client/rest"
+	serialClient "github.com/disaster37/gobot-arest/plateforms/arest/client/serial"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestRegisterTransport(t *testing.T) {
+	factory := func(u *url.URL) (Board, error) {
+		return newMockArestBoard(), nil
+	}
+
+	gobottest.Assert(t, RegisterTransport("mock", factory), nil)
+	gobottest.Assert(t, strings.Contains(strings.Join(Transports(), ","), "mock"), true)
+
+	// Already registered
+	gobottest.Refute(t, RegisterTransport("mock", factory), nil)
+	gobottest.Refute(t, RegisterTransport("http", factory), nil)
+
+	// Bad transport
+	gobottest.Refute(t, RegisterTransport("", factory), nil)
+	gobottest.Refute(t, RegisterTransport("mock2", nil), nil)
+
+	// Board from custom transport
+	board, err := NewBoard("mock://gateway/board1")
+	gobottest.Assert(t, err, nil)
+	_, ok := board.(*mockArestBoard)
+	gobottest.Assert(t, ok, true)
+}
+
+func TestNewBoard(t *testing.T) {
+	board, err := NewBoard("http://localhost:4567")
+	gobottest.Assert(t, err, nil)
+	_, ok := board.(*restClient.Client)
+	gobottest.Assert(t, ok, true)
+
+	board, err = NewBoard("serial:///dev/ttyUSB0")
+	gobottest.Assert(t, err, nil)
+	_, ok = board.(*serialClient.Client)
+	gobottest.Assert(t, ok, true)
+
+	// Bad URL
+	_, err = NewBoard("unknown://localhost")
+	gobottest.Refute(t, err, nil)
+	_, err = NewBoard("serial://")
+	gobottest.Refute(t, err, nil)
+}
+
+func TestNewAdaptorWithBoard(t *testing.T) {
+	a := NewAdaptorWithBoard(newMockArestBoard(), "TEST", true)
+	gobottest.Assert(t, a.Name(), "TEST")
+	gobottest.Assert(t, a.isDebug, true)
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.DigitalWrite("1", 1), nil)
+
+	a = NewAdaptorWithBoard(newMockArestBoard())
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "Arest"), true)
+}
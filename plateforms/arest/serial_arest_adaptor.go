@@ -1,9 +1,14 @@
 package arest
 
 import (
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
 	serialClient "github.com/disaster37/gobot-arest/plateforms/arest/client/serial"
+	"github.com/pkg/errors"
 	"go.bug.st/serial"
 	"gobot.io/x/gobot"
 )
@@ -13,18 +18,103 @@ type SerialAdaptor struct {
 	Adaptor
 }
 
+// DefaultSerialTimeout is the default timeout to wait serial response when no timeout is provided
+const DefaultSerialTimeout = serialClient.DefaultTimeout
+
+func init() {
+	if err := RegisterTransport("serial", newSerialBoard); err != nil {
+		panic(err)
+	}
+}
+
+// newSerialBoard create the serial board from URL like serial:///dev/ttyUSB0?baud=9600&parity=none&timeout=5s
+func newSerialBoard(u *url.URL) (board Board, err error) {
+	port := u.Path
+	if u.Host != "" {
+		// Permit relative path like serial://COM3
+		port = u.Host + u.Path
+	}
+	if port == "" {
+		return nil, errors.Errorf("No serial port found on URL %s", u.String())
+	}
+
+	opts, err := ParseURLOptions(u)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultSerialTimeout
+	}
+
+	mode, err := parseSerialMode(u.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return serialClient.NewClient(port, mode, opts.Timeout, opts.IsDebug), nil
+}
+
+// parseSerialMode permit to read serial mode from URL query
+func parseSerialMode(query url.Values) (mode *serial.Mode, err error) {
+	mode = &serial.Mode{
+		BaudRate: 115200,
+	}
+
+	if baud := query.Get("baud"); baud != "" {
+		if mode.BaudRate, err = strconv.Atoi(baud); err != nil || mode.BaudRate <= 0 {
+			return nil, errors.Errorf("Bad baud rate %s", baud)
+		}
+	}
+
+	if dataBits := query.Get("databits"); dataBits != "" {
+		if mode.DataBits, err = strconv.Atoi(dataBits); err != nil || mode.DataBits < 5 || mode.DataBits > 8 {
+			return nil, errors.Errorf("Bad data bits %s, it must be between 5 and 8", dataBits)
+		}
+	}
+
+	switch parity := strings.ToLower(query.Get("parity")); parity {
+	case "", "none":
+		mode.Parity = serial.NoParity
+	case "odd":
+		mode.Parity = serial.OddParity
+	case "even":
+		mode.Parity = serial.EvenParity
+	case "mark":
+		mode.Parity = serial.MarkParity
+	case "space":
+		mode.Parity = serial.SpaceParity
+	default:
+		return nil, errors.Errorf("Bad parity %s, it must be none, odd, even, mark or space", parity)
+	}
+
+	switch stopBits := query.Get("stopbits"); stopBits {
+	case "", "1":
+		mode.StopBits = serial.OneStopBit
+	case "1.5":
+		mode.StopBits = serial.OnePointFiveStopBits
+	case "2":
+		mode.StopBits = serial.TwoStopBits
+	default:
+		return nil, errors.Errorf("Bad stop bits %s, it must be 1, 1.5 or 2", stopBits)
+	}
+
+	return mode, nil
+}
+
 // NewSerialAdaptor returns a new serial Arest Adaptor which optionally accepts:
 //
 //	string: The board name
 //	time.Duration: The timeout for serial response
 //	bool: The debug mode
-//
-// serial.Mode: the serial mode
+//	serial.Mode: the serial mode
+//	client.BoardInfo: The expected board identity, checked on each connect
+//	client.ReconnectPolicy: The policy used to reconnect when connection is lost
+//	serialClient.Framing: The line terminators and max line length used to read responses
 func NewSerialAdaptor(port string, args ...interface{}) *Adaptor {
 	a := &Adaptor{
 		name:    gobot.DefaultName("SerialArest"),
 		isDebug: false,
-		timeout: 0,
+		timeout: DefaultSerialTimeout,
 		Eventer: gobot.NewEventer(),
 	}
 
@@ -32,6 +122,10 @@ func NewSerialAdaptor(port string, args ...interface{}) *Adaptor {
 		BaudRate: 115200,
 	}
 
+	var expected *client.BoardInfo
+	var policy *client.ReconnectPolicy
+	var framing *serialClient.Framing
+
 	for _, arg := range args {
 		switch argTmp := arg.(type) {
 		case string:
@@ -42,10 +136,20 @@ func NewSerialAdaptor(port string, args ...interface{}) *Adaptor {
 			a.isDebug = argTmp
 		case serial.Mode:
 			mode = argTmp
+		case client.BoardInfo:
+			expected = &argTmp
+		case client.ReconnectPolicy:
+			policy = &argTmp
+		case serialClient.Framing:
+			framing = &argTmp
 		}
 	}
 
-	a.Board = serialClient.NewClient(port, &mode, a.timeout, a.isDebug)
+	board := serialClient.NewClient(port, &mode, a.timeout, a.isDebug)
+	board.SetExpectedInfo(expected)
+	board.SetReconnectPolicy(policy)
+	board.SetFraming(framing)
+	a.setBoard(board)
 
 	return a
 }
@@ -5,11 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	serialClient "github.com/disaster37/gobot-arest/plateforms/arest/client/serial"
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
 )
 
 var _ gobot.Adaptor = (*SerialAdaptor)(nil)
+var _ Board = (*serialClient.Client)(nil)
 
 func initTestSerialAdaptor() *Adaptor {
 	a := NewSerialAdaptor("/dev/null")
@@ -21,10 +24,23 @@ func TestArestSerialAdaptor(t *testing.T) {
 	// With basic parameters
 	a := initTestSerialAdaptor()
 	gobottest.Assert(t, strings.HasPrefix(a.Name(), "SerialArest"), true)
+	gobottest.Assert(t, a.timeout, DefaultSerialTimeout)
 
 	// With all parameters
 	a = NewSerialAdaptor("/dev/null", 10*time.Second, "TEST", true)
 	gobottest.Assert(t, "TEST", a.Name())
 	gobottest.Assert(t, 10.*time.Second, a.timeout)
 	gobottest.Assert(t, true, a.isDebug)
+
+	// With expected board
+	a = NewSerialAdaptor("/dev/null", client.BoardInfo{ID: "002"})
+	gobottest.Assert(t, "002", a.Board.(*serialClient.Client).ExpectedInfo().ID)
+
+	// With reconnect policy
+	a = NewSerialAdaptor("/dev/null", client.ReconnectPolicy{MaxAttempts: 3})
+	gobottest.Assert(t, 3, a.Board.(*serialClient.Client).ReconnectPolicy().MaxAttempts)
+
+	// With framing
+	a = NewSerialAdaptor("/dev/null", serialClient.Framing{Terminators: []string{"\n"}})
+	gobottest.Assert(t, []string{"\n"}, a.Board.(*serialClient.Client).Framing().Terminators)
 }
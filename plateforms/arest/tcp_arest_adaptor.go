@@ -0,0 +1,105 @@
+package arest
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	serialClient "github.com/disaster37/gobot-arest/plateforms/arest/client/serial"
+	tcpClient "github.com/disaster37/gobot-arest/plateforms/arest/client/tcp"
+	"github.com/pkg/errors"
+	"go.bug.st/serial"
+	"gobot.io/x/gobot"
+)
+
+// TCPAdaptor is the Gobot Adaptor for Arest based boards reachable throught raw TCP socket
+type TCPAdaptor struct {
+	Adaptor
+}
+
+func init() {
+	if err := RegisterTransport("tcp", newTCPBoard); err != nil {
+		panic(err)
+	}
+}
+
+// newTCPBoard create the TCP board from URL like tcp://host:port?rfc2217=true&baud=9600&timeout=5s
+func newTCPBoard(u *url.URL) (board Board, err error) {
+	if u.Host == "" {
+		return nil, errors.Errorf("No address found on URL %s", u.String())
+	}
+
+	opts, err := ParseURLOptions(u)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultSerialTimeout
+	}
+
+	var mode *serial.Mode
+	if rfc2217 := u.Query().Get("rfc2217"); rfc2217 != "" {
+		isRFC2217, err := strconv.ParseBool(rfc2217)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Bad rfc2217 %s", rfc2217)
+		}
+		if isRFC2217 {
+			if mode, err = parseSerialMode(u.Query()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return tcpClient.NewClient(u.Host, mode, opts.Timeout, opts.IsDebug), nil
+}
+
+// NewTCPAdaptor returns a new TCP Arest Adaptor which optionally accepts:
+//
+//	string: The board name
+//	time.Duration: The timeout for TCP response
+//	bool: The debug mode
+//	serial.Mode: the serial mode, negociated with RFC2217 when it's provided
+//	client.BoardInfo: The expected board identity, checked on each connect
+//	client.ReconnectPolicy: The policy used to reconnect when connection is lost
+//	serialClient.Framing: The line terminators and max line length used to read responses
+func NewTCPAdaptor(address string, args ...interface{}) *Adaptor {
+	a := &Adaptor{
+		name:    gobot.DefaultName("TCPArest"),
+		isDebug: false,
+		timeout: DefaultSerialTimeout,
+		Eventer: gobot.NewEventer(),
+	}
+
+	var mode *serial.Mode
+	var expected *client.BoardInfo
+	var policy *client.ReconnectPolicy
+	var framing *serialClient.Framing
+
+	for _, arg := range args {
+		switch argTmp := arg.(type) {
+		case string:
+			a.name = argTmp
+		case time.Duration:
+			a.timeout = argTmp
+		case bool:
+			a.isDebug = argTmp
+		case serial.Mode:
+			mode = &argTmp
+		case client.BoardInfo:
+			expected = &argTmp
+		case client.ReconnectPolicy:
+			policy = &argTmp
+		case serialClient.Framing:
+			framing = &argTmp
+		}
+	}
+
+	board := tcpClient.NewClient(address, mode, a.timeout, a.isDebug)
+	board.SetExpectedInfo(expected)
+	board.SetReconnectPolicy(policy)
+	board.SetFraming(framing)
+	a.setBoard(board)
+
+	return a
+}
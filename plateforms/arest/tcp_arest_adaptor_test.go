@@ -0,0 +1,48 @@
+package arest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	tcpClient "github.com/disaster37/gobot-arest/plateforms/arest/client/tcp"
+	"go.bug.st/serial"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*TCPAdaptor)(nil)
+var _ Board = (*tcpClient.Client)(nil)
+
+func TestArestTCPAdaptor(t *testing.T) {
+
+	// With basic parameters
+	a := NewTCPAdaptor("localhost:23")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "TCPArest"), true)
+	gobottest.Assert(t, a.timeout, DefaultSerialTimeout)
+
+	// With all parameters
+	a = NewTCPAdaptor("localhost:23", 10*time.Second, "TEST", true, serial.Mode{BaudRate: 9600}, client.BoardInfo{ID: "002"})
+	gobottest.Assert(t, "TEST", a.Name())
+	gobottest.Assert(t, 10.*time.Second, a.timeout)
+	gobottest.Assert(t, true, a.isDebug)
+	gobottest.Assert(t, "002", a.Board.(*tcpClient.Client).ExpectedInfo().ID)
+}
+
+func TestNewTCPBoard(t *testing.T) {
+	a, err := NewAdaptorFromURL("tcp://192.168.1.10:23?rfc2217=true&baud=9600&name=pool")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Name(), "pool")
+	board, ok := a.Board.(*tcpClient.Client)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, board.Address(), "192.168.1.10:23")
+
+	// Bad URL
+	_, err = NewAdaptorFromURL("tcp://192.168.1.10:23?rfc2217=bad")
+	gobottest.Refute(t, err, nil)
+	_, err = NewAdaptorFromURL("tcp://192.168.1.10:23?rfc2217=true&parity=bad")
+	gobottest.Refute(t, err, nil)
+	_, err = NewAdaptorFromURL("tcp:///dev/null")
+	gobottest.Refute(t, err, nil)
+}
@@ -0,0 +1,115 @@
+package arest
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	restClient "github.com/disaster37/gobot-arest/plateforms/arest/client/rest"
+	serialClient "github.com/disaster37/gobot-arest/plateforms/arest/client/serial"
+	"go.bug.st/serial"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestParseURLOptions(t *testing.T) {
+	u, _ := url.Parse("http://localhost?name=pool&timeout=5s&debug=true")
+	opts, err := ParseURLOptions(u)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, opts, &URLOptions{Name: "pool", Timeout: 5 * time.Second, IsDebug: true})
+
+	u, _ = url.Parse("http://localhost?timeout=bad")
+	_, err = ParseURLOptions(u)
+	gobottest.Refute(t, err, nil)
+
+	u, _ = url.Parse("http://localhost?debug=bad")
+	_, err = ParseURLOptions(u)
+	gobottest.Refute(t, err, nil)
+
+	// Reconnect policy
+	u, _ = url.Parse("http://localhost?reconnect_interval=100ms&reconnect_max_interval=10s&reconnect_max_attempts=5&reconnect_max_elapsed=1m")
+	opts, err = ParseURLOptions(u)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, opts.ReconnectPolicy, &client.ReconnectPolicy{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+		MaxAttempts:     5,
+		MaxElapsedTime:  1 * time.Minute,
+	})
+
+	for _, query := range []string{"reconnect_interval=bad", "reconnect_max_interval=bad", "reconnect_max_attempts=-1", "reconnect_max_elapsed=bad"} {
+		u, _ = url.Parse("http://localhost?" + query)
+		_, err = ParseURLOptions(u)
+		gobottest.Refute(t, err, nil)
+	}
+}
+
+func TestParseSerialMode(t *testing.T) {
+	u, _ := url.Parse("serial:///dev/ttyUSB0?baud=9600&parity=even&databits=7&stopbits=2")
+	mode, err := parseSerialMode(u.Query())
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, mode, &serial.Mode{BaudRate: 9600, Parity: serial.EvenParity, DataBits: 7, StopBits: serial.TwoStopBits})
+
+	// Default
+	mode, err = parseSerialMode(url.Values{})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, mode.BaudRate, 115200)
+
+	// Bad values
+	for _, query := range []string{"baud=bad", "databits=9", "parity=bad", "stopbits=3"} {
+		values, _ := url.ParseQuery(query)
+		_, err = parseSerialMode(values)
+		gobottest.Refute(t, err, nil)
+	}
+}
+
+func TestNewAdaptorFromURL(t *testing.T) {
+
+	// Serial
+	a, err := NewAdaptorFromURL("serial:///dev/ttyUSB0?baud=9600&parity=none&timeout=5s&name=pool")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Name(), "pool")
+	gobottest.Assert(t, a.timeout, 5*time.Second)
+	_, ok := a.Board.(*serialClient.Client)
+	gobottest.Assert(t, ok, true)
+
+	// HTTP
+	a, err = NewAdaptorFromURL("http://localhost:4567?debug=true")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "Arest"), true)
+	gobottest.Assert(t, a.isDebug, true)
+	board, ok := a.Board.(*restClient.Client)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, board.Client().BaseURL, "http://localhost:4567")
+
+	// Args override URL options
+	a, err = NewAdaptorFromURL("https://localhost?name=pool", "TEST")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Name(), "TEST")
+	a, err = NewAdaptorFromURL("http://localhost?timeout=5s&proxy=http://proxy:3128", 10*time.Second, true)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.timeout, 10*time.Second)
+	gobottest.Assert(t, a.isDebug, true)
+	board = a.Board.(*restClient.Client)
+	gobottest.Assert(t, board.Client().GetClient().Timeout, 10*time.Second)
+	gobottest.Assert(t, board.Client().IsProxySet(), true)
+
+	// Reconnect policy
+	a, err = NewAdaptorFromURL("serial:///dev/ttyUSB0?reconnect_max_attempts=3")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Board.(*serialClient.Client).ReconnectPolicy().MaxAttempts, 3)
+	a, err = NewAdaptorFromURL("serial:///dev/ttyUSB0?reconnect_max_attempts=3", client.ReconnectPolicy{MaxAttempts: 5})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Board.(*serialClient.Client).ReconnectPolicy().MaxAttempts, 5)
+
+	// Bad URL
+	_, err = NewAdaptorFromURL("serial:///dev/ttyUSB0?baud=bad")
+	gobottest.Refute(t, err, nil)
+	_, err = NewAdaptorFromURL("unknown://localhost")
+	gobottest.Refute(t, err, nil)
+	_, err = NewAdaptorFromURL("http://localhost?timeout=bad")
+	gobottest.Refute(t, err, nil)
+}
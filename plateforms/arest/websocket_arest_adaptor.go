@@ -0,0 +1,88 @@
+package arest
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	websocketClient "github.com/disaster37/gobot-arest/plateforms/arest/client/websocket"
+	"gobot.io/x/gobot"
+)
+
+// WebSocketAdaptor is the Gobot Adaptor for Arest based boards reachable throught WebSocket gateway
+type WebSocketAdaptor struct {
+	Adaptor
+}
+
+func init() {
+	for _, scheme := range []string{"ws", "wss"} {
+		if err := RegisterTransport(scheme, newWebSocketBoard); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// newWebSocketBoard create the WebSocket board from URL like ws://host:port/arest?timeout=5s&debug=true
+// Other query parameters are kept on gateway URL.
+func newWebSocketBoard(u *url.URL) (board Board, err error) {
+	opts, err := ParseURLOptions(u)
+	if err != nil {
+		return nil, err
+	}
+
+	// Options are not part of the gateway URL
+	query := u.Query()
+	for _, option := range []string{"name", "timeout", "debug", "reconnect_interval", "reconnect_max_interval", "reconnect_max_attempts", "reconnect_max_elapsed"} {
+		query.Del(option)
+	}
+	gatewayURL := *u
+	gatewayURL.RawQuery = query.Encode()
+
+	return websocketClient.NewClient(gatewayURL.String(), nil, opts.Timeout, opts.IsDebug), nil
+}
+
+// NewWebSocketAdaptor returns a new WebSocket Arest Adaptor which optionally accepts:
+//
+//	string: The board name
+//	time.Duration: The timeout to wait gateway response
+//	bool: The debug mode
+//	http.Header: The headers sent on handshake
+//	client.BoardInfo: The expected board identity, checked on each connect
+//	client.ReconnectPolicy: The policy used to reconnect when connection is lost
+func NewWebSocketAdaptor(url string, args ...interface{}) *Adaptor {
+	a := &Adaptor{
+		name:    gobot.DefaultName("WebSocketArest"),
+		isDebug: false,
+		timeout: websocketClient.DefaultTimeout,
+		Eventer: gobot.NewEventer(),
+	}
+
+	var header http.Header
+	var expected *client.BoardInfo
+	var policy *client.ReconnectPolicy
+
+	for _, arg := range args {
+		switch argTmp := arg.(type) {
+		case string:
+			a.name = argTmp
+		case time.Duration:
+			a.timeout = argTmp
+		case bool:
+			a.isDebug = argTmp
+		case http.Header:
+			header = argTmp
+		case client.BoardInfo:
+			expected = &argTmp
+		case client.ReconnectPolicy:
+			policy = &argTmp
+		}
+	}
+
+	board := websocketClient.NewClient(url, header, a.timeout, a.isDebug)
+	board.SetExpectedInfo(expected)
+	board.SetReconnectPolicy(policy)
+	a.setBoard(board)
+
+	return a
+}
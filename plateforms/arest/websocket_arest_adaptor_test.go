@@ -0,0 +1,44 @@
+package arest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disaster37/gobot-arest/plateforms/arest/client"
+	websocketClient "github.com/disaster37/gobot-arest/plateforms/arest/client/websocket"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*WebSocketAdaptor)(nil)
+var _ Board = (*websocketClient.Client)(nil)
+
+func TestArestWebSocketAdaptor(t *testing.T) {
+
+	// With basic parameters
+	a := NewWebSocketAdaptor("ws://localhost:8080/arest")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "WebSocketArest"), true)
+	gobottest.Assert(t, a.timeout, websocketClient.DefaultTimeout)
+
+	// With all parameters
+	a = NewWebSocketAdaptor("ws://localhost:8080/arest", 5*time.Second, "TEST", true, http.Header{"X-Api-Key": []string{"secret"}}, client.BoardInfo{ID: "002"})
+	gobottest.Assert(t, "TEST", a.Name())
+	gobottest.Assert(t, 5*time.Second, a.timeout)
+	gobottest.Assert(t, true, a.isDebug)
+	gobottest.Assert(t, "002", a.Board.(*websocketClient.Client).ExpectedInfo().ID)
+}
+
+func TestNewWebSocketBoard(t *testing.T) {
+	a, err := NewAdaptorFromURL("wss://gateway:8443/arest?token=abc&timeout=5s&name=pool")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, a.Name(), "pool")
+	board, ok := a.Board.(*websocketClient.Client)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, board.URL(), "wss://gateway:8443/arest?token=abc")
+
+	// Bad URL
+	_, err = NewAdaptorFromURL("ws://gateway:8080/arest?timeout=bad")
+	gobottest.Refute(t, err, nil)
+}